@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -34,13 +35,34 @@ type AuthSpec struct {
 	// The Audiences to use with the JWT Token
 	// +kubebuilder:validation:Required
 	Audiences []string `json:"audiences"`
-	// +kubebuilder:validation:Enum=quay;googleArtifactRegistry
+	// +kubebuilder:validation:Enum=quay;googleArtifactRegistry;awsElasticContainerRegistry;azureContainerRegistry;artifactory;tokenExchange;vault;googleSecretManager;awsSecretsManager;harbor;plugin;mock
 	// +kubebuilder:default:=quay
 	// +kubebuilder:validation:Required
 	ContainerRegistry string `json:"containerRegistry"`
 	// Must be one of below
-	Quay                   Quay                   `json:"quay,omitempty"`
-	GoogleArtifactRegistry GoogleArtifactRegistry `json:"googleArtifactRegistry,omitempty"`
+	Quay                        Quay                        `json:"quay,omitempty"`
+	GoogleArtifactRegistry      GoogleArtifactRegistry      `json:"googleArtifactRegistry,omitempty"`
+	AWSElasticContainerRegistry AWSElasticContainerRegistry `json:"awsElasticContainerRegistry,omitempty"`
+	AzureContainerRegistry      AzureContainerRegistry      `json:"azureContainerRegistry,omitempty"`
+	Artifactory                 Artifactory                 `json:"artifactory,omitempty"`
+	TokenExchange               TokenExchange               `json:"tokenExchange,omitempty"`
+	Vault                       Vault                       `json:"vault,omitempty"`
+	GoogleSecretManager         GoogleSecretManager         `json:"googleSecretManager,omitempty"`
+	AWSSecretsManager           AWSSecretsManager           `json:"awsSecretsManager,omitempty"`
+	Harbor                      Harbor                      `json:"harbor,omitempty"`
+	Plugin                      Plugin                      `json:"plugin,omitempty"`
+	Mock                        Mock                        `json:"mock,omitempty"`
+	// Several Registries Rendered into the One Secret, when Set the Provider Fields Above are Ignored
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	Registries []Registry `json:"registries,omitempty"`
+	// Reference the Secret from Service Accounts' imagePullSecrets, Only Supported by Auths
+	// +kubebuilder:validation:Optional
+	LinkToServiceAccounts *LinkToServiceAccounts `json:"linkToServiceAccounts,omitempty"`
+	// When to Refresh the Credentials, Relative to When they Expire
+	// +kubebuilder:validation:Optional
+	Rotation *Rotation `json:"rotation,omitempty"`
 }
 
 type Quay struct {
@@ -84,14 +106,413 @@ type GoogleArtifactRegistry struct {
 	GoogleProviderName string `json:"googleProviderName,omitempty"`
 }
 
+type AWSElasticContainerRegistry struct {
+	// The ARN of the IAM Role to Assume with the Kubernetes Service Account Token
+	// +kubebuilder:validation:Required
+	RoleARN string `json:"roleArn"`
+	// The AWS Region the Registry is Located in
+	// +kubebuilder:validation:Required
+	Region string `json:"region"`
+	// The AWS Account IDs of the Registries to Authenticate to, defaults to the Account of the Role
+	// +kubebuilder:validation:Optional
+	RegistryIDs []string `json:"registryIds,omitempty"`
+	// Override the STS Endpoint, defaults to https://sts.REGION.amazonaws.com
+	// +kubebuilder:validation:Optional
+	STSEndpoint string `json:"stsEndpoint,omitempty"`
+	// Override the ECR API Endpoint, defaults to https://api.ecr.REGION.amazonaws.com
+	// +kubebuilder:validation:Optional
+	ECREndpoint string `json:"ecrEndpoint,omitempty"`
+}
+
+type AzureContainerRegistry struct {
+	// The Login Server of the Registry, for example myregistry.azurecr.io
+	// +kubebuilder:validation:Required
+	Registry string `json:"registry"`
+	// The Entra Tenant ID the Federated Application or Managed Identity Belongs to
+	// +kubebuilder:validation:Required
+	TenantID string `json:"tenantId"`
+	// The Client ID of the Application or Managed Identity with the Federated Credential
+	// +kubebuilder:validation:Required
+	ClientID string `json:"clientId"`
+	// Override the Entra Authority Host, defaults to https://login.microsoftonline.com
+	// +kubebuilder:validation:Optional
+	AuthorityHost string `json:"authorityHost,omitempty"`
+	// Override the Registry Endpoint used for the Token Exchange, defaults to https://REGISTRY
+	// +kubebuilder:validation:Optional
+	RegistryEndpoint string `json:"registryEndpoint,omitempty"`
+	// Issue Short Lived Passwords for a Scope Map Token Instead of a Refresh Token, Limiting the Secret to the Scope Map's Repositories and Actions
+	// +kubebuilder:validation:Optional
+	ScopeMapToken *AzureScopeMapToken `json:"scopeMapToken,omitempty"`
+}
+
+type AzureScopeMapToken struct {
+	// The Subscription the Registry is Located in
+	// +kubebuilder:validation:Required
+	SubscriptionID string `json:"subscriptionId"`
+	// The Resource Group the Registry is Located in
+	// +kubebuilder:validation:Required
+	ResourceGroup string `json:"resourceGroup"`
+	// The Name of the Registry Token Bound to the Scope Map
+	// +kubebuilder:validation:Required
+	TokenName string `json:"tokenName"`
+	// Override the Azure Resource Manager Endpoint, defaults to https://management.azure.com
+	// +kubebuilder:validation:Optional
+	ResourceManagerEndpoint string `json:"resourceManagerEndpoint,omitempty"`
+}
+
+type Artifactory struct {
+	// The Artifactory URL, for example example.jfrog.io or https://artifactory.example.com
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+	// The Name of the OIDC Integration Configured in Artifactory
+	// +kubebuilder:validation:Required
+	ProviderName string `json:"providerName"`
+	// Scope the Token to a JFrog Project
+	// +kubebuilder:validation:Optional
+	ProjectKey string `json:"projectKey,omitempty"`
+	// The Docker Registry Host to Write to the Secret, defaults to the Host of the URL
+	// +kubebuilder:validation:Optional
+	Registry string `json:"registry,omitempty"`
+}
+
+type TokenExchange struct {
+	// The RFC 8693 Token Endpoint
+	// +kubebuilder:validation:Required
+	TokenEndpoint string `json:"tokenEndpoint"`
+	// The Registry Hosts to Write to the Secret, at Least One is Required.
+	// Checked by the Provider, as Typed Clients Send the Unused Provider Sections with a null List
+	// +kubebuilder:validation:Optional
+	Registries []string `json:"registries,omitempty"`
+	// The Type of the Kubernetes Service Account Token Sent as the subject_token
+	// +kubebuilder:default:="urn:ietf:params:oauth:token-type:jwt"
+	// +kubebuilder:validation:Optional
+	SubjectTokenType string `json:"subjectTokenType,omitempty"`
+	// The requested_token_type Parameter
+	// +kubebuilder:validation:Optional
+	RequestedTokenType string `json:"requestedTokenType,omitempty"`
+	// The audience Parameters
+	// +kubebuilder:validation:Optional
+	Audience []string `json:"audience,omitempty"`
+	// The resource Parameters
+	// +kubebuilder:validation:Optional
+	Resource []string `json:"resource,omitempty"`
+	// The Space Separated scope Parameter
+	// +kubebuilder:validation:Optional
+	Scope string `json:"scope,omitempty"`
+	// Authenticate the Client with Credentials from a Secret
+	// +kubebuilder:validation:Optional
+	ClientAuth *TokenExchangeClientAuth `json:"clientAuth,omitempty"`
+	// Dot Separated Path to the Token in the JSON Response
+	// +kubebuilder:default:=access_token
+	// +kubebuilder:validation:Optional
+	TokenPath string `json:"tokenPath,omitempty"`
+	// Go Template for the Docker Username, with .Response (the JSON Response) and .Claims (the Issued Token's Claims, if a JWT)
+	// +kubebuilder:default:=token
+	// +kubebuilder:validation:Optional
+	UsernameTemplate string `json:"usernameTemplate,omitempty"`
+}
+
+type TokenExchangeClientAuth struct {
+	// Name of the Secret in the Auth's Namespace Holding the Client Credentials
+	// +kubebuilder:validation:Required
+	SecretName string `json:"secretName"`
+	// Key of the Client ID in the Secret
+	// +kubebuilder:default:=client_id
+	// +kubebuilder:validation:Optional
+	ClientIDKey string `json:"clientIdKey,omitempty"`
+	// Key of the Client Secret in the Secret
+	// +kubebuilder:default:=client_secret
+	// +kubebuilder:validation:Optional
+	ClientSecretKey string `json:"clientSecretKey,omitempty"`
+	// How the Client Credentials are Sent, basic (Authorization Header) or post (Form Body)
+	// +kubebuilder:validation:Enum=basic;post
+	// +kubebuilder:default:=basic
+	// +kubebuilder:validation:Optional
+	Method string `json:"method,omitempty"`
+}
+
+type Vault struct {
+	// The Vault Address, for example https://vault.example.com:8200
+	// +kubebuilder:validation:Required
+	Address string `json:"address"`
+	// The Vault Enterprise Namespace
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// The Auth Method Type, kubernetes or jwt
+	// +kubebuilder:validation:Enum=kubernetes;jwt
+	// +kubebuilder:default:=kubernetes
+	// +kubebuilder:validation:Optional
+	AuthMethod string `json:"authMethod,omitempty"`
+	// The Path the Auth Method is Mounted at, defaults to the Auth Method Type
+	// +kubebuilder:validation:Optional
+	AuthMount string `json:"authMount,omitempty"`
+	// The Vault Role to Login as
+	// +kubebuilder:validation:Required
+	Role string `json:"role"`
+	// The Path to Read the Credentials From, for example secret/data/dockerhub for KV Version 2
+	// +kubebuilder:validation:Required
+	Path string `json:"path"`
+	// The Key of the Username in the Secret
+	// +kubebuilder:default:=username
+	// +kubebuilder:validation:Optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+	// The Key of the Password in the Secret
+	// +kubebuilder:default:=password
+	// +kubebuilder:validation:Optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+	// The Registry Hosts to Write to the Secret, at Least One is Required.
+	// Checked by the Provider, as Typed Clients Send the Unused Provider Sections with a null List
+	// +kubebuilder:validation:Optional
+	Registries []string `json:"registries,omitempty"`
+}
+
+type GoogleSecretManager struct {
+	// The Secret Version to Read, for example projects/my-project/secrets/dockerhub/versions/latest
+	// The Payload Must be a JSON Object Containing the Username and Password
+	// +kubebuilder:validation:Required
+	SecretVersion string `json:"secretVersion"`
+	// The Registry Hosts to Write to the Secret, at Least One is Required.
+	// Checked by the Provider, as Typed Clients Send the Unused Provider Sections with a null List
+	// +kubebuilder:validation:Optional
+	Registries []string `json:"registries,omitempty"`
+	// The Key of the Username in the Secret Payload
+	// +kubebuilder:default:=username
+	// +kubebuilder:validation:Optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+	// The Key of the Password in the Secret Payload
+	// +kubebuilder:default:=password
+	// +kubebuilder:validation:Optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+	// Override the Secret Manager Endpoint, defaults to https://secretmanager.googleapis.com
+	// +kubebuilder:validation:Optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// Object Type, must be configMap or inline
+	// +kubebuilder:validation:Enum=configMap;inline
+	// +kubebuilder:default:=inline
+	Type string `json:"type,omitempty"`
+	// The Name of the Kubernetes Object Containing the Workload Identity Json Config
+	// +kubebuilder:validation:Optional
+	ObjectName string `json:"objectName,omitempty"`
+	// The Name of the File Within the Object, Generally: credentials_config.json
+	// +kubebuilder:validation:Optional
+	FileName string `json:"fileName,omitempty"`
+	// The Google Service Account That is to be Bound to a Kubernetes Service Account with Secret Manager Secret Accessor
+	// +kubebuilder:validation:Optional
+	GoogleServiceAccount string `json:"googleServiceAccount,omitempty"`
+	// The GCP Project in which the Workload Identity Pool/Provider is Located
+	// +kubebuilder:validation:Optional
+	GooglePoolProject string `json:"googlePoolProject,omitempty"`
+	// Name of the Workload Identity Pool
+	// +kubebuilder:validation:Optional
+	GooglePoolName string `json:"googlePoolName,omitempty"`
+	// Name of the Workload Identity Pool Provider
+	// +kubebuilder:validation:Optional
+	GoogleProviderName string `json:"googleProviderName,omitempty"`
+}
+
+type AWSSecretsManager struct {
+	// The ARN of the IAM Role to Assume with the Kubernetes Service Account Token
+	// +kubebuilder:validation:Required
+	RoleARN string `json:"roleArn"`
+	// The AWS Region the Secret is Located in
+	// +kubebuilder:validation:Required
+	Region string `json:"region"`
+	// The Name or ARN of the Secret, the SecretString Must be a JSON Object Containing the Username and Password
+	// +kubebuilder:validation:Required
+	SecretID string `json:"secretId"`
+	// The Version Stage to Read
+	// +kubebuilder:default:=AWSCURRENT
+	// +kubebuilder:validation:Optional
+	VersionStage string `json:"versionStage,omitempty"`
+	// The Registry Hosts to Write to the Secret, at Least One is Required.
+	// Checked by the Provider, as Typed Clients Send the Unused Provider Sections with a null List
+	// +kubebuilder:validation:Optional
+	Registries []string `json:"registries,omitempty"`
+	// The Key of the Username in the Secret String
+	// +kubebuilder:default:=username
+	// +kubebuilder:validation:Optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+	// The Key of the Password in the Secret String
+	// +kubebuilder:default:=password
+	// +kubebuilder:validation:Optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+	// Override the STS Endpoint, defaults to https://sts.REGION.amazonaws.com
+	// +kubebuilder:validation:Optional
+	STSEndpoint string `json:"stsEndpoint,omitempty"`
+	// Override the Secrets Manager Endpoint, defaults to https://secretsmanager.REGION.amazonaws.com
+	// +kubebuilder:validation:Optional
+	SecretsManagerEndpoint string `json:"secretsManagerEndpoint,omitempty"`
+}
+
+type Harbor struct {
+	// The Harbor URL, for example harbor.example.com or https://harbor.example.com
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+	// The Harbor Project the Robot Account is Scoped to
+	// +kubebuilder:validation:Required
+	Project string `json:"project"`
+	// Name of the Secret in the Auth's Namespace Holding the Harbor Admin Credentials
+	// +kubebuilder:validation:Required
+	AdminSecretName string `json:"adminSecretName"`
+	// The Key of the Admin Username in the Secret
+	// +kubebuilder:default:=username
+	// +kubebuilder:validation:Optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+	// The Key of the Admin Password in the Secret
+	// +kubebuilder:default:=password
+	// +kubebuilder:validation:Optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+	// Prefix of the Generated Robot Account Names, defaults to NAMESPACE-NAME of the Auth
+	// +kubebuilder:validation:Optional
+	RobotPrefix string `json:"robotPrefix,omitempty"`
+	// How Many Days Each Robot Account is Valid for
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Optional
+	DurationDays int `json:"durationDays,omitempty"`
+	// The Repository Actions Granted to the Robot Account
+	// +kubebuilder:default:={pull}
+	// +kubebuilder:validation:Optional
+	Actions []string `json:"actions,omitempty"`
+}
+
+type Plugin struct {
+	// Name of the Plugin, as Configured in the Controller's Plugin Config
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Parameters Passed to the Plugin
+	// +kubebuilder:validation:Optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+type Mock struct {
+	// The Registry Host to Write to the Secret
+	// +kubebuilder:default:=registry.local
+	// +kubebuilder:validation:Optional
+	Registry string `json:"registry,omitempty"`
+	// How Long the Signed Token is Valid for
+	// +kubebuilder:default:="1h"
+	// +kubebuilder:validation:Optional
+	TokenLifetime metav1.Duration `json:"tokenLifetime,omitempty"`
+}
+
+type Registry struct {
+	// Identifies the Registry in the Status
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// The Audiences to use with the JWT Token, Defaults to the Auth's Audiences
+	// +kubebuilder:validation:Optional
+	Audiences []string `json:"audiences,omitempty"`
+	// +kubebuilder:validation:Enum=quay;googleArtifactRegistry;awsElasticContainerRegistry;azureContainerRegistry;artifactory;tokenExchange;vault;googleSecretManager;awsSecretsManager;harbor;plugin;mock
+	// +kubebuilder:validation:Required
+	ContainerRegistry string `json:"containerRegistry"`
+	// Must be one of below
+	Quay                        Quay                        `json:"quay,omitempty"`
+	GoogleArtifactRegistry      GoogleArtifactRegistry      `json:"googleArtifactRegistry,omitempty"`
+	AWSElasticContainerRegistry AWSElasticContainerRegistry `json:"awsElasticContainerRegistry,omitempty"`
+	AzureContainerRegistry      AzureContainerRegistry      `json:"azureContainerRegistry,omitempty"`
+	Artifactory                 Artifactory                 `json:"artifactory,omitempty"`
+	TokenExchange               TokenExchange               `json:"tokenExchange,omitempty"`
+	Vault                       Vault                       `json:"vault,omitempty"`
+	GoogleSecretManager         GoogleSecretManager         `json:"googleSecretManager,omitempty"`
+	AWSSecretsManager           AWSSecretsManager           `json:"awsSecretsManager,omitempty"`
+	Harbor                      Harbor                      `json:"harbor,omitempty"`
+	Plugin                      Plugin                      `json:"plugin,omitempty"`
+	Mock                        Mock                        `json:"mock,omitempty"`
+}
+
+type LinkToServiceAccounts struct {
+	// Service Accounts to Link by Name, Defaults to the Auth's Service Account when No Selector is Set Either
+	// +kubebuilder:validation:Optional
+	Names []string `json:"names,omitempty"`
+	// Link Every Service Account in the Namespace Matching the Selector
+	// +kubebuilder:validation:Optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Also Add the Secret to the Service Accounts' secrets, and Annotate it with tekton.dev/docker-N for Tekton Pipelines
+	// +kubebuilder:validation:Optional
+	Tekton bool `json:"tekton,omitempty"`
+}
+
+type Rotation struct {
+	// How Long Before Expiry to Refresh, a Duration such as 10m, or a Percentage of the Credentials' Lifetime such as 20%
+	// +kubebuilder:validation:Pattern=`^(([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+|([0-9]|[1-9][0-9])%)$`
+	// +kubebuilder:default:="60s"
+	// +kubebuilder:validation:Optional
+	RefreshBefore string `json:"refreshBefore,omitempty"`
+	// The Shortest Time Between Refreshes, so Short Lived Credentials are not Refreshed in a Loop, it Never Delays a Refresh Past the Expiry
+	// +kubebuilder:default:="60s"
+	// +kubebuilder:validation:Optional
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
+	// Refresh Up to this Percentage of the Wait Earlier, at Random, so Auths Issued Together do not Refresh Together
+	// +kubebuilder:validation:Pattern=`^([0-9]|[1-9][0-9])%$`
+	// +kubebuilder:validation:Optional
+	Jitter string `json:"jitter,omitempty"`
+}
+
 // AuthStatus defines the observed state of Auth
 type AuthStatus struct {
-	// When the Current Token Expires
+	// When the Current Token Expires, the Earliest Expiration when Several Registries are Configured
 	TokenExpiration string `json:"tokenExpiration,omitempty"`
+	// TokenExpiration as a Timestamp
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+	// When Credentials were Last Issued
+	LastRefreshTime *metav1.Time `json:"lastRefreshTime,omitempty"`
+	// When the Credentials will Next be Refreshed
+	NextRefreshTime *metav1.Time `json:"nextRefreshTime,omitempty"`
+	// The Generation of the Spec Last Reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The Image Pull Secret Written, a ClusterAuth Writes it to Each Namespace in Status.Namespaces
+	SecretRef *corev1.SecretReference `json:"secretRef,omitempty"`
 	// The configs used to setup the federation settings
 	FederationConfiguration FederationConfiguration `json:"federationConfiguration,omitempty"`
 	// Output of Any Errors
 	Error string `json:"error,omitempty"`
+	// Status of Each of Spec.Registries
+	Registries []RegistryStatus `json:"registries,omitempty"`
+	// The Registry Hosts Written to the Secret
+	Hosts []string `json:"hosts,omitempty"`
+	// The Service Accounts the Secret is Currently Linked To
+	ServiceAccountLinks ServiceAccountLinks `json:"serviceAccountLinks,omitempty"`
+	// The Retry Schedule of the Last Failure, Cleared Once Credentials are Written or the Spec Changes
+	Retry *RetryStatus `json:"retry,omitempty"`
+	// Ready, TokenIssued, SecretSynced, Degraded and PolicyCompliant Conditions
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// Retried with Exponential Backoff
+	RetryTransient = "Transient"
+	// Retried when the Spec, or an Object it References, Changes
+	RetryPermanent = "Permanent"
+)
+
+type RetryStatus struct {
+	// Consecutive Failed Attempts
+	Attempts int32 `json:"attempts"`
+	// Whether the Failure is Transient or Permanent
+	// +kubebuilder:validation:Enum=Transient;Permanent
+	Classification string `json:"classification"`
+	// When the Next Attempt is Scheduled, Unset for Permanent Failures
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+}
+
+type ServiceAccountLinks struct {
+	SecretName      string   `json:"secretName,omitempty"`
+	ServiceAccounts []string `json:"serviceAccounts,omitempty"`
+}
+
+type RegistryStatus struct {
+	Name string `json:"name"`
+	// When the Registry's Token Expires
+	TokenExpiration string `json:"tokenExpiration,omitempty"`
+	// When the Registry's Token Expires, Machine Readable
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+	// The Registry Hosts the Registry's Credentials are Written For
+	Hosts []string `json:"hosts,omitempty"`
+	// Output of Any Errors for this Registry
+	Error string `json:"error,omitempty"`
 }
 
 type FederationConfiguration struct {
@@ -101,6 +522,10 @@ type FederationConfiguration struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Registry",type=string,JSONPath=`.spec.containerRegistry`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Expiry",type=date,JSONPath=`.status.expirationTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Auth is the Schema for the auths API
 type Auth struct {
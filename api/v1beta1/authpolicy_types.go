@@ -0,0 +1,87 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AuthPolicySpec defines the desired state of AuthPolicy
+type AuthPolicySpec struct {
+	// The Namespaces the Policy Applies To, Every Namespace when Empty
+	// +kubebuilder:validation:Optional
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Values of Spec.ContainerRegistry
+	// +kubebuilder:validation:Optional
+	ContainerRegistries PatternRule `json:"containerRegistries,omitempty"`
+	// Kubernetes Service Accounts Auths may Mint Tokens For
+	// +kubebuilder:validation:Optional
+	ServiceAccounts PatternRule `json:"serviceAccounts,omitempty"`
+	// Audiences of the Kubernetes Tokens
+	// +kubebuilder:validation:Optional
+	Audiences PatternRule `json:"audiences,omitempty"`
+	// Quay Robot Accounts, for Example myorg+*
+	// +kubebuilder:validation:Optional
+	RobotAccounts PatternRule `json:"robotAccounts,omitempty"`
+	// Quay Hosts
+	// +kubebuilder:validation:Optional
+	QuayURLs PatternRule `json:"quayURLs,omitempty"`
+	// Google Service Accounts Impersonated by Inline Google Configurations
+	// +kubebuilder:validation:Optional
+	GoogleServiceAccounts PatternRule `json:"googleServiceAccounts,omitempty"`
+	// The Longest Registry Credentials may be Valid For
+	// +kubebuilder:validation:Optional
+	MaxTokenLifetime *metav1.Duration `json:"maxTokenLifetime,omitempty"`
+	// How Many Auths a Namespace may Have, the Oldest Auths are Allowed
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxAuthsPerNamespace *int32 `json:"maxAuthsPerNamespace,omitempty"`
+}
+
+// PatternRule Matches Values Against Glob Patterns, where * Matches Any Characters
+type PatternRule struct {
+	// Values must Match One of these Patterns, Any Value is Allowed when Empty
+	// +kubebuilder:validation:Optional
+	Allow []string `json:"allow,omitempty"`
+	// Values must not Match Any of these Patterns
+	// +kubebuilder:validation:Optional
+	Deny []string `json:"deny,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// AuthPolicy is the Schema for the authpolicies API
+type AuthPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AuthPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AuthPolicyList contains a list of AuthPolicy
+type AuthPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuthPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuthPolicy{}, &AuthPolicyList{})
+}
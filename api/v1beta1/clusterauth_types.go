@@ -0,0 +1,76 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterAuthSpec defines the desired state of ClusterAuth
+// +kubebuilder:validation:XValidation:rule="!has(self.linkToServiceAccounts)",message="linkToServiceAccounts is only supported by Auths"
+type ClusterAuthSpec struct {
+	// The Credentials are Minted Once, Using the Service Account and Config Maps in the Controller's Namespace
+	AuthSpec `json:",inline"`
+	// The Namespaces to Write the Image Pull Secret To
+	// +kubebuilder:validation:Required
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+}
+
+// ClusterAuthStatus defines the observed state of ClusterAuth
+type ClusterAuthStatus struct {
+	AuthStatus `json:",inline"`
+	// Sync Status of Each Matching Namespace
+	Namespaces []NamespaceStatus `json:"namespaces,omitempty"`
+}
+
+type NamespaceStatus struct {
+	Name string `json:"name"`
+	// Whether the Image Pull Secret is Up to Date in this Namespace
+	Synced bool `json:"synced"`
+	// Output of Any Errors for this Namespace
+	Error string `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Registry",type=string,JSONPath=`.spec.containerRegistry`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Expiry",type=date,JSONPath=`.status.expirationTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterAuth is the Schema for the clusterauths API
+type ClusterAuth struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterAuthSpec   `json:"spec,omitempty"`
+	Status ClusterAuthStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterAuthList contains a list of ClusterAuth
+type ClusterAuthList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterAuth `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterAuth{}, &ClusterAuthList{})
+}
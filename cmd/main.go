@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"os"
@@ -37,6 +38,11 @@ import (
 
 	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
 	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/internal/controller"
+	webhookv1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/internal/webhook/v1"
+	webhookv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/internal/webhook/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/mock"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/plugin"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/tracing"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -58,6 +64,13 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var pluginConfig string
+	var enableMockProvider bool
+	var mockRegistryAddr string
+	var controllerNamespace string
+	var enablePodWebhook bool
+	var enableAuthWebhooks bool
+	var tracingOptions tracing.Options
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -69,6 +82,29 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&pluginConfig, "plugin-config", "",
+		"Path to the provider plugin config. Only the plugins listed in it can be used by Auths with containerRegistry: plugin.")
+	flag.BoolVar(&enableMockProvider, "enable-mock-provider", false,
+		"If set, Auths can use containerRegistry: mock, which signs its own tokens. For development clusters and tests only.")
+	flag.StringVar(&mockRegistryAddr, "mock-registry-bind-address", "0", "The address the mock registry auth endpoint binds to, "+
+		"accepting tokens issued by the mock provider. Requires --enable-mock-provider, leave as 0 to disable it.")
+	flag.StringVar(&controllerNamespace, "controller-namespace", os.Getenv("POD_NAMESPACE"),
+		"The namespace the controller runs in. ClusterAuths mint credentials with the service accounts and config maps in it.")
+	flag.BoolVar(&enablePodWebhook, "enable-pod-webhook", false,
+		"If set, the Pod mutating webhook adds the secrets of ready Auths matching a Pod's images to its imagePullSecrets. "+
+			"Requires the webhook serving certificates.")
+	flag.BoolVar(&enableAuthWebhooks, "enable-auth-webhooks", false,
+		"If set, the Auth defaulting and validating webhooks, and the ClusterAuth validating webhook, reject misconfigured "+
+			"Auths and ClusterAuths on admission, and those of users who could not mint their credentials themselves. "+
+			"Requires the webhook serving certificates.")
+	flag.StringVar(&tracingOptions.Exporter, "tracing-exporter", tracing.ExporterNone,
+		"Where OpenTelemetry spans of token minting, registry exchanges and secret writes are exported: none, otlp or stdout.")
+	flag.StringVar(&tracingOptions.Endpoint, "tracing-endpoint", "",
+		"The OTLP gRPC collector's host:port, defaults to OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4317.")
+	flag.BoolVar(&tracingOptions.Insecure, "tracing-insecure", false,
+		"If set, spans are exported to the OTLP collector without TLS.")
+	flag.Float64Var(&tracingOptions.SampleRatio, "tracing-sample-ratio", 1,
+		"The fraction of reconciles traced, between 0 and 1.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -76,6 +112,18 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	ctx := ctrl.SetupSignalHandler()
+
+	shutdownTracing, err := tracing.Setup(ctx, tracingOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "unable to flush traces")
+		}
+	}()
 
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -122,6 +170,8 @@ func main() {
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  controller.CacheOptions(),
+		Client:                 controller.ClientOptions(),
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
@@ -144,13 +194,66 @@ func main() {
 		os.Exit(1)
 	}
 
+	var mockSigner *mock.Signer
+	if enableMockProvider {
+		mockSigner, err = mock.NewSigner()
+		if err != nil {
+			setupLog.Error(err, "unable to create mock signer")
+			os.Exit(1)
+		}
+		if mockRegistryAddr != "0" {
+			if err := mgr.Add(mock.NewRegistryServer(mockRegistryAddr, mockSigner)); err != nil {
+				setupLog.Error(err, "unable to set up mock registry")
+				os.Exit(1)
+			}
+		}
+	}
+
+	providers := controller.DefaultProviders(mockSigner)
+	if pluginConfig != "" {
+		config, err := plugin.LoadConfig(pluginConfig)
+		if err != nil {
+			setupLog.Error(err, "unable to load plugin config")
+			os.Exit(1)
+		}
+		providers.Register(plugin.Name, plugin.New(config))
+	}
+
 	if err = (&controller.AuthReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Providers: providers,
+		Recorder:  mgr.GetEventRecorderFor("auth-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Auth")
 		os.Exit(1)
 	}
+	if err = (&controller.ClusterAuthReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Providers: providers,
+		Namespace: controllerNamespace,
+		Recorder:  mgr.GetEventRecorderFor("clusterauth-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterAuth")
+		os.Exit(1)
+	}
+	if enablePodWebhook {
+		if err = webhookv1.SetupPodWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Pod")
+			os.Exit(1)
+		}
+	}
+	if enableAuthWebhooks {
+		if err = webhookv1beta1.SetupAuthWebhookWithManager(mgr, providers); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Auth")
+			os.Exit(1)
+		}
+		if err = webhookv1beta1.SetupClusterAuthWebhookWithManager(mgr, providers, controllerNamespace); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterAuth")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -163,7 +266,7 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
@@ -18,42 +18,69 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	coreV1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
-	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/google"
-	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/jwt"
 	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/kubernetes"
-	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/quay"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
 )
 
 func BoolPointer(b bool) *bool {
 	return &b
 }
 
+// Lifetime of the Kubernetes Service Account Tokens Minted for Federation
+const tokenExpirationSeconds = 3600
+
+var tracer = otel.Tracer("github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/internal/controller")
+
 // AuthReconciler reconciles a Auth object
 type AuthReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Providers available to Spec.ContainerRegistry, defaults to DefaultProviders
+	Providers *provider.Registry
+	// Records Token Issuance, Rotation and Failures on the Auth, defaults to the Manager's
+	Recorder record.EventRecorder
+
+	retries *retryRateLimiter
 }
 
-func updateContainerRegistryObject(r *AuthReconciler, reconcilerContext context.Context, containerRegistryAuth containerregistryv1beta1.Auth, expirationSeconds int) (ctrl.Result, error) {
+// updateContainerRegistryObject Updates the Auth's Status, and Requeues it After requeueAfter,
+// or Returns retryErr so the Workqueue Retries it
+func updateContainerRegistryObject(r *AuthReconciler, reconcilerContext context.Context, containerRegistryAuth containerregistryv1beta1.Auth, requeueAfter time.Duration, retryErr error) (ctrl.Result, error) {
+	finalizeStatus(&containerRegistryAuth.Status, containerRegistryAuth.Generation, requeueAfter)
+
 	if err := r.Status().Update(reconcilerContext, &containerRegistryAuth); err != nil {
 		return ctrl.Result{}, fmt.Errorf("unable to update Container Registry Auth status: %w", err)
+	} else if retryErr != nil {
+		return ctrl.Result{}, retryErr
 	} else {
-		if expirationSeconds == 0 {
-			expirationSeconds = 36000
-		}
-		return ctrl.Result{RequeueAfter: time.Second * time.Duration(expirationSeconds-60)}, nil
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 }
 
@@ -63,9 +90,11 @@ func updateContainerRegistryObject(r *AuthReconciler, reconcilerContext context.
 
 // CUSTOM RBAC
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=create;delete;update
-//+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;delete;update
+//+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;patch
 //+kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
+//+kubebuilder:rbac:groups=containerregistry.arthurvardevanyan.com,resources=authpolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -80,9 +109,14 @@ func (r *AuthReconciler) Reconcile(reconcilerContext context.Context, req ctrl.R
 	log := log.FromContext(reconcilerContext)
 	log.V(1).Info(req.Name)
 
+	reconcilerContext, span := tracer.Start(reconcilerContext, "Auth.Reconcile", trace.WithAttributes(
+		attribute.String("k8s.namespace.name", req.Namespace),
+		attribute.String("auth.name", req.Name),
+	))
+	defer span.End()
+
 	// Common Variables
-	const tokenExpirationSeconds = 3600
-	var err error
+	var err, retryErr error
 	var error string
 
 	// Incept Object
@@ -90,6 +124,7 @@ func (r *AuthReconciler) Reconcile(reconcilerContext context.Context, req ctrl.R
 	if err = r.Get(reconcilerContext, req.NamespacedName, &containerRegistryAuth); err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			log.V(1).Info("Artifact Registry Auth Object Not Found or No Longer Exists!")
+			tokenExpiry.delete(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		} else {
 			log.Error(err, "Unable to fetch Artifact Registry Auth Object")
@@ -97,6 +132,31 @@ func (r *AuthReconciler) Reconcile(reconcilerContext context.Context, req ctrl.R
 		}
 	}
 
+	// Unlink Service Accounts Before the Auth is Deleted
+	if !containerRegistryAuth.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(&containerRegistryAuth, serviceAccountFinalizer) {
+			containerRegistryAuth.Spec.LinkToServiceAccounts = nil
+			if err = r.linkServiceAccounts(reconcilerContext, &containerRegistryAuth); err != nil {
+				log.Error(err, "Unable to Unlink Service Accounts")
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(&containerRegistryAuth, serviceAccountFinalizer)
+			if err = r.Update(reconcilerContext, &containerRegistryAuth); err != nil {
+				return ctrl.Result{}, client.IgnoreNotFound(err)
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+	if containerRegistryAuth.Spec.LinkToServiceAccounts != nil || len(containerRegistryAuth.Status.ServiceAccountLinks.ServiceAccounts) > 0 {
+		if controllerutil.AddFinalizer(&containerRegistryAuth, serviceAccountFinalizer) {
+			if err = r.Update(reconcilerContext, &containerRegistryAuth); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	resetRetry(&containerRegistryAuth.Status, containerRegistryAuth.Generation)
+
 	var ownerRef = metaV1.OwnerReference{
 		APIVersion:         containerRegistryAuth.APIVersion,
 		Kind:               containerRegistryAuth.Kind,
@@ -107,115 +167,154 @@ func (r *AuthReconciler) Reconcile(reconcilerContext context.Context, req ctrl.R
 	}
 	ownerReference := []metaV1.OwnerReference{ownerRef}
 
-	//Reset Error
-	containerRegistryAuth.Status.Error = ""
-	containerRegistryAuth.Status.TokenExpiration = ""
-	containerRegistryAuth.Status.FederationConfiguration.Issuer = ""
-	containerRegistryAuth.Status.FederationConfiguration.Subject = ""
-
-	var dockerConfig string
+	// Enforce AuthPolicies Before Calling Any Provider
+	violations, maxTokenLifetime, err := evaluatePolicies(reconcilerContext, r.Client, &containerRegistryAuth)
+	if err != nil {
+		error = "Unable to Evaluate Auth Policies"
+		resetStatus(&containerRegistryAuth)
+		containerRegistryAuth.Status.Error = err.Error()
+		setCondition(&containerRegistryAuth.Status, containerRegistryAuth.Generation, ConditionPolicyCompliant, metaV1.ConditionUnknown, "PolicyEvaluationFailed", err.Error())
+		log.Error(err, error)
+		return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, 0, r.retries.scheduleRetry(req, &containerRegistryAuth.Status, err, 0))
+	}
+	if len(violations) > 0 {
+		resetStatus(&containerRegistryAuth)
+		containerRegistryAuth.Status.Error = strings.Join(violations, "; ")
+		setCondition(&containerRegistryAuth.Status, containerRegistryAuth.Generation, ConditionPolicyCompliant, metaV1.ConditionFalse, "PolicyViolation", containerRegistryAuth.Status.Error)
+		setCondition(&containerRegistryAuth.Status, containerRegistryAuth.Generation, ConditionTokenIssued, metaV1.ConditionFalse, "PolicyViolation", "Credentials are not Issued for Auths Violating an AuthPolicy")
+		log.Info("Auth Denied by Auth Policies", "violations", violations)
+		return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, 0, r.retries.scheduleRetry(req, &containerRegistryAuth.Status, permanent(errors.New(containerRegistryAuth.Status.Error)), 0))
+	}
+	setCondition(&containerRegistryAuth.Status, containerRegistryAuth.Generation, ConditionPolicyCompliant, metaV1.ConditionTrue, "Compliant", "The Auth Complies with the Auth Policies of its Namespace")
+
+	// Reuse the Credentials in the Image Pull Secret While they are Fresh, so Restarts do not Mint New Ones,
+	// and Registries that Failed are not Exchanged Again Before their Retry is Due
+	hash := specHash(reconcilerContext, r.Client, r.Providers, &containerRegistryAuth)
+	existingSecret := &coreV1.Secret{}
+	if r.Get(reconcilerContext, types.NamespacedName{Name: containerRegistryAuth.Spec.SecretName, Namespace: containerRegistryAuth.Namespace}, existingSecret) != nil {
+		existingSecret = nil
+	}
+	if credentialsReusable(&containerRegistryAuth.Status, containerRegistryAuth.Generation) && existingSecret != nil {
+		if metadata, fresh := freshCredentials(existingSecret, &containerRegistryAuth, containerRegistryAuth.Spec.Rotation, hash, maxTokenLifetime); fresh {
+			log.V(1).Info("Reusing Fresh Credentials", "issuedAt", metadata.IssuedAt, "expiration", metadata.Expiration)
+			reuseCredentials(&containerRegistryAuth, &containerRegistryAuth, metadata)
+			containerRegistryAuth.Status.SecretRef = &coreV1.SecretReference{Name: existingSecret.Name, Namespace: existingSecret.Namespace}
+			setCondition(&containerRegistryAuth.Status, containerRegistryAuth.Generation, ConditionSecretSynced, metaV1.ConditionTrue, "SecretWritten", "The Image Pull Secret Holds the Latest Credentials")
+
+			if err = r.linkServiceAccounts(reconcilerContext, &containerRegistryAuth); err != nil {
+				error = "Unable to Link Service Accounts"
+				containerRegistryAuth.Status.Error = error
+				log.Error(err, error)
+			}
+
+			requeueAfter := refreshAfter(containerRegistryAuth.Spec.Rotation, metadata.IssuedAt, metadata.Expiration)
+			if untilRetry, pending := pendingRetry(&containerRegistryAuth.Status, containerRegistryAuth.Generation); pending {
+				requeueAfter = min(requeueAfter, untilRetry)
+			}
+			return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, requeueAfter, nil)
+		}
+	}
 
-	if containerRegistryAuth.Spec.ContainerRegistry == "quay" {
+	// Registries that Fail Keep their Previous Credentials Until they Expire
+	imagePullSecretAuths, expiration, exchangeErr := exchangeRegistries(reconcilerContext, r.Client, r.Providers, r.Recorder, &containerRegistryAuth, &containerRegistryAuth, maxTokenLifetime,
+		previousCredentials(existingSecret, &containerRegistryAuth))
+	if imagePullSecretAuths == nil {
+		return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, 0, r.retries.scheduleRetry(req, &containerRegistryAuth.Status, exchangeErr, 0))
+	}
+	dockerConfig := imagePullSecretAuths.String()
 
-		kubernetesAuth := kubernetes.New(r.Client)
+	// Create Image Pull Secret
+	imagePullSecret := kubernetes.ImagePullSecretObject(containerRegistryAuth.Spec.SecretName, req.NamespacedName.Namespace, dockerConfig, ownerReference, pullSecretMetadata(&containerRegistryAuth, expiration, hash))
+	if link := containerRegistryAuth.Spec.LinkToServiceAccounts; link != nil && link.Tekton {
+		maps.Copy(imagePullSecret.Annotations, tektonAnnotations(imagePullSecretAuths.Registries()))
+	}
+	created, err := writeImagePullSecret(reconcilerContext, r.Client, imagePullSecret)
+	if err != nil {
+		error = "Unable to Create Image Pull Secret"
+		containerRegistryAuth.Status.Error = error
+		setCondition(&containerRegistryAuth.Status, containerRegistryAuth.Generation, ConditionSecretSynced, metaV1.ConditionFalse, "SecretWriteFailed", err.Error())
+		r.Recorder.Eventf(&containerRegistryAuth, coreV1.EventTypeWarning, EventSecretWriteFailed, "%s '%s': %v", error, imagePullSecret.Name, err)
+		log.Error(err, error)
+		return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, 0, r.retries.scheduleRetry(req, &containerRegistryAuth.Status, err, 0))
+	}
+	if created {
+		r.Recorder.Eventf(&containerRegistryAuth, coreV1.EventTypeNormal, EventSecretCreated, "Created Image Pull Secret '%s'", imagePullSecret.Name)
+	} else {
+		r.Recorder.Eventf(&containerRegistryAuth, coreV1.EventTypeNormal, EventSecretUpdated, "Updated Image Pull Secret '%s'", imagePullSecret.Name)
+	}
+	containerRegistryAuth.Status.SecretRef = &coreV1.SecretReference{Name: imagePullSecret.Name, Namespace: imagePullSecret.Namespace}
+	setCondition(&containerRegistryAuth.Status, containerRegistryAuth.Generation, ConditionSecretSynced, metaV1.ConditionTrue, "SecretWritten", "The Image Pull Secret Holds the Latest Credentials")
 
-		kubernetesToken, err := kubernetesAuth.GetKubernetesAuthToken(reconcilerContext, containerRegistryAuth.Spec.ServiceAccount, req.NamespacedName.Namespace, tokenExpirationSeconds, containerRegistryAuth.Spec.Audiences)
-		if err != nil {
-			error = "Unable to Generate Kubernetes Token"
-			containerRegistryAuth.Status.Error = err.Error()
-			log.Error(err, error)
-			return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, 0)
-		}
+	err = r.linkServiceAccounts(reconcilerContext, &containerRegistryAuth)
+	if err != nil {
+		error = "Unable to Link Service Accounts"
+		containerRegistryAuth.Status.Error = error
+		log.Error(err, error)
+	}
 
-		kubernetesTokenIssuer, err := jwt.Issuer(kubernetesToken.Status.Token)
-		if err != nil {
-			error = "Unable to Generate Kubernetes Token Issuer"
-			containerRegistryAuth.Status.Error = err.Error()
-			log.Error(err, error)
-			return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, 0)
-		}
-		containerRegistryAuth.Status.FederationConfiguration.Issuer = kubernetesTokenIssuer
-
-		kubernetesTokenSubject, err := jwt.Subject(kubernetesToken.Status.Token)
-		if err != nil {
-			error = "Unable to Generate Kubernetes Token Subject"
-			containerRegistryAuth.Status.Error = err.Error()
-			log.Error(err, error)
-			return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, 0)
-		}
-		containerRegistryAuth.Status.FederationConfiguration.Subject = kubernetesTokenSubject
-
-		quayToken, err := quay.GetQuayRobotToken(kubernetesToken.Status.Token, containerRegistryAuth.Spec.Quay.RobotAccount, containerRegistryAuth.Spec.Quay.URL)
-		if err != nil {
-			error = "Unable to Generate Quay Token"
-			containerRegistryAuth.Status.Error = error + ": " + err.Error()
-			log.Error(err, error)
-			return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, 0)
-		}
+	// Retry Registries that Failed, Without Waiting for the Next Refresh
+	requeueAfter := refreshAfter(containerRegistryAuth.Spec.Rotation, containerRegistryAuth.Status.LastRefreshTime.Time, expiration)
+	if exchangeErr != nil {
+		retryErr = r.retries.scheduleRetry(req, &containerRegistryAuth.Status, exchangeErr, requeueAfter)
+	} else {
+		containerRegistryAuth.Status.Retry = nil
+	}
+	return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, requeueAfter, retryErr)
+}
 
-		quayTokenExpiration, err := jwt.TokenExpiration(quayToken)
-		if err != nil {
-			error = "Unable to Generate Quay Token Expiration"
-			containerRegistryAuth.Status.Error = err.Error()
-			log.Error(err, error)
-			return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, 0)
-		}
-		containerRegistryAuth.Status.TokenExpiration = quayTokenExpiration
+// authsForPolicy Requests Every Auth when an AuthPolicy Changes
+func (r *AuthReconciler) authsForPolicy(reconcilerContext context.Context, _ client.Object) []reconcile.Request {
+	return r.listAuths(reconcilerContext)
+}
 
-		if err != nil {
-			error = "Unable to Generate Quay Token"
-			containerRegistryAuth.Status.Error = err.Error()
-			log.Error(err, error)
-			return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, 0)
-		}
-		dockerConfig = kubernetes.ImagePullSecretConfig(containerRegistryAuth.Spec.Quay.RobotAccount, quayToken, containerRegistryAuth.Spec.Quay.URL)
-	}
-
-	if containerRegistryAuth.Spec.ContainerRegistry == "googleArtifactRegistry" {
-
-		wifConfig := google.New(
-			r.Client, containerRegistryAuth.Namespace,
-			containerRegistryAuth.Spec.GoogleArtifactRegistry.ObjectName,
-			containerRegistryAuth.Spec.GoogleArtifactRegistry.FileName,
-			containerRegistryAuth.Spec.ServiceAccount,
-			containerRegistryAuth.Spec.GoogleArtifactRegistry.GoogleServiceAccount,
-			containerRegistryAuth.Spec.GoogleArtifactRegistry.GooglePoolProject,
-			containerRegistryAuth.Spec.GoogleArtifactRegistry.GooglePoolName,
-			containerRegistryAuth.Spec.GoogleArtifactRegistry.GoogleProviderName,
-			containerRegistryAuth.Spec.GoogleArtifactRegistry.Type,
-			containerRegistryAuth.Spec.Audiences,
-		)
-		wifTokenSource, err := wifConfig.GetGcpWifTokenWithTokenSource(reconcilerContext)
-		if err != nil {
-			containerRegistryAuth.Status.Error = err.Error()
-			log.Error(err, "Failed to Generate GCP Wif Token from Provided Configuration")
-			return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, 0)
-		}
+// authsInNamespace Requests the Auths in the Namespace of the Object
+func (r *AuthReconciler) authsInNamespace(reconcilerContext context.Context, object client.Object) []reconcile.Request {
+	return r.listAuths(reconcilerContext, client.InNamespace(object.GetNamespace()))
+}
 
-		containerRegistryAuth.Status.TokenExpiration = wifTokenSource.RawToken.Expiry.Local().String()
-		// Create Image Pull Secret
-		dockerConfig = kubernetes.ImagePullSecretConfig("oauth2accesstoken", wifTokenSource.RawToken.AccessToken, containerRegistryAuth.Spec.GoogleArtifactRegistry.RegistryLocation+"-docker.pkg.dev")
+func (r *AuthReconciler) listAuths(reconcilerContext context.Context, opts ...client.ListOption) []reconcile.Request {
+	var auths containerregistryv1beta1.AuthList
+	if err := r.List(reconcilerContext, &auths, opts...); err != nil {
+		log.FromContext(reconcilerContext).Error(err, "Unable to List Auth Objects")
+		return nil
 	}
 
-	// Create Image Pull Secret
-	imagePullSecret := kubernetes.ImagePullSecretObject(containerRegistryAuth.Spec.SecretName, req.NamespacedName.Namespace, dockerConfig, ownerReference)
-	err = r.Update(reconcilerContext, imagePullSecret)
-	if err != nil {
-		err = r.Create(reconcilerContext, imagePullSecret)
-		if err != nil {
-			error = "Unable to Create Image Pull Secret"
-			containerRegistryAuth.Status.Error = error
-			log.Error(err, error)
-			return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, 0)
-		}
+	requests := make([]reconcile.Request, 0, len(auths.Items))
+	for _, auth := range auths.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&auth)})
 	}
-
-	return updateContainerRegistryObject(r, reconcilerContext, containerRegistryAuth, tokenExpirationSeconds)
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *AuthReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Providers == nil {
+		r.Providers = DefaultProviders(nil)
+	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("auth-controller")
+	}
+	r.retries = newRetryRateLimiter()
+	if err := indexReferences(mgr, &containerregistryv1beta1.Auth{}, func(object client.Object) *containerregistryv1beta1.Auth {
+		return object.(*containerregistryv1beta1.Auth)
+	}); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&containerregistryv1beta1.Auth{}).
+		WithOptions(controller.Options{RateLimiter: r.retries}).
+		// Status Updates do not Trigger a Reconcile, so Retries Follow their Backoff
+		For(&containerregistryv1beta1.Auth{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// A Deleted or Edited Secret is Written Again, and a Recreated Service Account is Linked Again
+		Owns(&coreV1.Secret{}, builder.WithPredicates(secretChangedPredicate)).
+		Watches(&coreV1.ServiceAccount{}, handler.EnqueueRequestsFromMapFunc(r.authsReferencing(serviceAccountIndex))).
+		Watches(&coreV1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.authsReferencing(configMapIndex))).
+		Watches(&containerregistryv1beta1.AuthPolicy{}, handler.EnqueueRequestsFromMapFunc(r.authsForPolicy)).
+		// Deleting an Auth can Bring Others Under an AuthPolicy's maxAuthsPerNamespace
+		Watches(&containerregistryv1beta1.Auth{}, handler.EnqueueRequestsFromMapFunc(r.authsInNamespace),
+			builder.WithPredicates(predicate.Funcs{
+				CreateFunc:  func(event.CreateEvent) bool { return false },
+				UpdateFunc:  func(event.UpdateEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			})).
 		Complete(r)
 }
@@ -17,19 +17,47 @@ limitations under the License.
 package controller
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/kubernetes"
 )
 
+// eventReasons Returns the Reasons of the Events Recorded on the Object Since it was Created
+func eventReasons(object client.Object) []string {
+	var events v1.EventList
+	if err := k8sClient.List(ctx, &events, client.InNamespace(object.GetNamespace())); err != nil {
+		return nil
+	}
+	var reasons []string
+	for _, event := range events.Items {
+		if event.InvolvedObject.UID == object.GetUID() {
+			reasons = append(reasons, event.Reason)
+		}
+	}
+	return reasons
+}
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
@@ -564,4 +592,1358 @@ var _ = Describe("Container Registry", func() {
 		})
 	})
 
+	Context("Creating an Auth Object For AWS Elastic Container Registry", func() {
+		It("Should Assume a Role with the Kubernetes Token, and Create a Secret with an ECR Token", func() {
+			By("By creating a new Container Registry Auth Object against a local STS and ECR")
+			sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				if r.Form.Get("Action") != "AssumeRoleWithWebIdentity" || r.Form.Get("WebIdentityToken") == "" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				fmt.Fprint(w, `<AssumeRoleWithWebIdentityResponse><AssumeRoleWithWebIdentityResult><Credentials>`+
+					`<AccessKeyId>AKIDEXAMPLE</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>session</SessionToken>`+
+					`<Expiration>2030-01-01T00:00:00Z</Expiration></Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`)
+			}))
+			defer sts.Close()
+			ecr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				fmt.Fprintf(w, `{"authorizationData":[{"authorizationToken":"%s","expiresAt":%d,"proxyEndpoint":"https://123456789012.dkr.ecr.us-east-1.amazonaws.com"}]}`,
+					base64.StdEncoding.EncodeToString([]byte("AWS:ecr-token")), time.Now().Add(12*time.Hour).Unix())
+			}))
+			defer ecr.Close()
+
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         []string{"sts.amazonaws.com"},
+					ContainerRegistry: "awsElasticContainerRegistry",
+					AWSElasticContainerRegistry: containerregistryv1beta1.AWSElasticContainerRegistry{
+						RoleARN:     "arn:aws:iam::123456789012:role/ecr-pull",
+						Region:      "us-east-1",
+						STSEndpoint: sts.URL,
+						ECREndpoint: ecr.URL,
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+			Expect(string(createdSecret.Data[".dockerconfigjson"])).Should(ContainSubstring("123456789012.dkr.ecr.us-east-1.amazonaws.com"))
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+		})
+	})
+
+	Context("Creating an Auth Object For Azure Container Registry", func() {
+		It("Should Exchange the Kubernetes Token with Entra, and Create a Secret with an ACR Refresh Token", func() {
+			By("By creating a new Container Registry Auth Object against a local Entra and ACR")
+			entra := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				if r.URL.Path != "/tenant/oauth2/v2.0/token" || r.Form.Get("client_assertion") == "" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				fmt.Fprint(w, `{"access_token":"aad-token","expires_in":3600}`)
+			}))
+			defer entra.Close()
+			acr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				if r.URL.Path != "/oauth2/exchange" || r.Form.Get("access_token") != "aad-token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				fmt.Fprint(w, `{"refresh_token":"acr-refresh-token"}`)
+			}))
+			defer acr.Close()
+
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         []string{"api://AzureADTokenExchange"},
+					ContainerRegistry: "azureContainerRegistry",
+					AzureContainerRegistry: containerregistryv1beta1.AzureContainerRegistry{
+						Registry:         "example.azurecr.io",
+						TenantID:         "tenant",
+						ClientID:         "client",
+						AuthorityHost:    entra.URL,
+						RegistryEndpoint: acr.URL,
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+			Expect(string(createdSecret.Data[".dockerconfigjson"])).Should(ContainSubstring(
+				base64.StdEncoding.EncodeToString([]byte("00000000-0000-0000-0000-000000000000:acr-refresh-token"))))
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+		})
+	})
+
+	Context("Creating an Auth Object For Artifactory", func() {
+		It("Should Exchange the Kubernetes Token with the OIDC Integration, and Create a Secret with an Access Token", func() {
+			By("By creating a new Container Registry Auth Object against a local Artifactory")
+			artifactory := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var request map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&request)
+				if r.URL.Path != "/access/api/v1/oidc/token" || request["provider_name"] != "kubernetes" || request["subject_token"] == "" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				fmt.Fprint(w, `{"access_token":"artifactory-token","expires_in":3600,"username":"k8s-puller"}`)
+			}))
+			defer artifactory.Close()
+
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "artifactory",
+					Artifactory: containerregistryv1beta1.Artifactory{
+						URL:          artifactory.URL,
+						ProviderName: "kubernetes",
+						Registry:     "artifactory.example.com",
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+			Expect(string(createdSecret.Data[".dockerconfigjson"])).Should(ContainSubstring(
+				base64.StdEncoding.EncodeToString([]byte("k8s-puller:artifactory-token"))))
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+		})
+	})
+
+	Context("Creating an Auth Object For a Generic Token Exchange", func() {
+		It("Should Exchange the Kubernetes Token at the Token Endpoint, and Create a Secret with the Issued Token", func() {
+			By("By creating a new Container Registry Auth Object against a local RFC 8693 Token Endpoint")
+			tokenEndpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:token-exchange" || r.Form.Get("audience") != "registry.example.com" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				fmt.Fprint(w, `{"access_token":"exchanged-token","issued_token_type":"urn:ietf:params:oauth:token-type:access_token","expires_in":600,"username":"exchanged-user"}`)
+			}))
+			defer tokenEndpoint.Close()
+
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "tokenExchange",
+					TokenExchange: containerregistryv1beta1.TokenExchange{
+						TokenEndpoint:    tokenEndpoint.URL,
+						Registries:       []string{"registry.example.com"},
+						Audience:         []string{"registry.example.com"},
+						UsernameTemplate: "{{ .Response.username }}",
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+			Expect(string(createdSecret.Data[".dockerconfigjson"])).Should(ContainSubstring(
+				base64.StdEncoding.EncodeToString([]byte("exchanged-user:exchanged-token"))))
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+		})
+	})
+
+	Context("Creating an Auth Object For Vault", func() {
+		It("Should Login to Vault with the Kubernetes Token, and Create a Secret from a KV Secret", func() {
+			By("By creating a new Container Registry Auth Object against a local Vault")
+			vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1/auth/kubernetes/login":
+					fmt.Fprint(w, `{"auth":{"client_token":"vault-token","lease_duration":1800}}`)
+				case "/v1/secret/data/dockerhub":
+					if r.Header.Get("X-Vault-Token") != "vault-token" {
+						w.WriteHeader(http.StatusForbidden)
+						return
+					}
+					fmt.Fprint(w, `{"data":{"data":{"username":"docker-user","password":"docker-password"},"metadata":{"version":1}},"lease_duration":0}`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer vault.Close()
+
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         []string{"vault"},
+					ContainerRegistry: "vault",
+					Vault: containerregistryv1beta1.Vault{
+						Address:    vault.URL,
+						Role:       "registry",
+						Path:       "secret/data/dockerhub",
+						Registries: []string{"docker.io"},
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+			Expect(string(createdSecret.Data[".dockerconfigjson"])).Should(ContainSubstring(
+				base64.StdEncoding.EncodeToString([]byte("docker-user:docker-password"))))
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+		})
+	})
+
+	Context("Creating an Auth Object For AWS Secrets Manager", func() {
+		It("Should Assume a Role with the Kubernetes Token, and Create a Secret from a Secrets Manager Secret", func() {
+			By("By creating a new Container Registry Auth Object against a local STS and Secrets Manager")
+			sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `<AssumeRoleWithWebIdentityResponse><AssumeRoleWithWebIdentityResult><Credentials>`+
+					`<AccessKeyId>AKIDEXAMPLE</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>session</SessionToken>`+
+					`<Expiration>2030-01-01T00:00:00Z</Expiration></Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`)
+			}))
+			defer sts.Close()
+			secretsManager := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				fmt.Fprint(w, `{"SecretString":"{\"username\":\"robot\",\"password\":\"robot-password\"}"}`)
+			}))
+			defer secretsManager.Close()
+
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         []string{"sts.amazonaws.com"},
+					ContainerRegistry: "awsSecretsManager",
+					AWSSecretsManager: containerregistryv1beta1.AWSSecretsManager{
+						RoleARN:                "arn:aws:iam::123456789012:role/registry-credentials",
+						Region:                 "us-east-1",
+						SecretID:               "harbor-robot",
+						Registries:             []string{"harbor.example.com"},
+						STSEndpoint:            sts.URL,
+						SecretsManagerEndpoint: secretsManager.URL,
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+			Expect(string(createdSecret.Data[".dockerconfigjson"])).Should(ContainSubstring(
+				base64.StdEncoding.EncodeToString([]byte("robot:robot-password"))))
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+		})
+	})
+
+	Context("Creating an Auth Object For Harbor", func() {
+		It("Should Create a Robot Account, Delete Superseded Robots, and Create a Secret with the Robot Secret", func() {
+			By("By creating a new Container Registry Auth Object against a local Harbor")
+			var deletedRobots sync.Map
+			harbor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if username, password, ok := r.BasicAuth(); !ok || username != "admin" || password != "Harbor12345" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/api/v2.0/robots":
+					w.WriteHeader(http.StatusCreated)
+					fmt.Fprintf(w, `{"id":3,"name":"robot$library+smoke-tests-test-3","secret":"robot-secret","expires_at":%d}`, time.Now().Add(24*time.Hour).Unix())
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v2.0/robots":
+					fmt.Fprint(w, `[{"id":1,"name":"robot$library+smoke-tests-test-1"},{"id":2,"name":"robot$library+smoke-tests-test-2"},{"id":3,"name":"robot$library+smoke-tests-test-3"}]`)
+				case r.Method == http.MethodDelete:
+					deletedRobots.Store(r.URL.Path, true)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer harbor.Close()
+
+			adminSecret := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "harbor-admin",
+					Namespace: ObjectNamespace,
+				},
+				StringData: map[string]string{"username": "admin", "password": "Harbor12345"},
+			}
+			k8sClient.Delete(ctx, adminSecret)
+			Expect(k8sClient.Create(ctx, adminSecret)).Should(Succeed())
+
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "harbor",
+					Harbor: containerregistryv1beta1.Harbor{
+						URL:             harbor.URL,
+						Project:         "library",
+						AdminSecretName: "harbor-admin",
+						RobotPrefix:     "smoke-tests-test",
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+			Expect(string(createdSecret.Data[".dockerconfigjson"])).Should(ContainSubstring(
+				base64.StdEncoding.EncodeToString([]byte("robot$library+smoke-tests-test-3:robot-secret"))))
+			_, deleted := deletedRobots.Load("/api/v2.0/robots/1")
+			Expect(deleted).Should(BeTrue())
+			_, deleted = deletedRobots.Load("/api/v2.0/robots/2")
+			Expect(deleted).Should(BeFalse())
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+			k8sClient.Delete(ctx, adminSecret)
+		})
+	})
+
+	Context("Creating an Auth Object For a Plugin", func() {
+		It("Should Run the Plugin, and Create a Secret with the Plugin's Credentials", func() {
+			By("By creating a new Container Registry Auth Object using an allowed plugin")
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "plugin",
+					Plugin: containerregistryv1beta1.Plugin{
+						Name:       "static",
+						Parameters: map[string]string{"repository": "example"},
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+			Expect(string(createdSecret.Data[".dockerconfigjson"])).Should(ContainSubstring(
+				base64.StdEncoding.EncodeToString([]byte("plugin-user:plugin-password"))))
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+		})
+
+		It("Should Reject Plugins Missing From the Plugin Config", func() {
+			By("By creating a new Container Registry Auth Object using an unknown plugin")
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "plugin",
+					Plugin: containerregistryv1beta1.Plugin{
+						Name: "unknown",
+					},
+				},
+			}
+
+			k8sClient.Delete(ctx, Auth)
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			objectLookUpKey := types.NamespacedName{Name: ObjectName, Namespace: ObjectNamespace}
+			createdObject := &containerregistryv1beta1.Auth{}
+			Eventually(func() bool {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				return createdObject.Status.Error != ""
+			}, timeout, interval).Should(BeTrue())
+			Expect(createdObject.Status.Error).Should(Equal("plugin 'unknown' is not allowed by the controller's plugin config"))
+
+			k8sClient.Delete(ctx, Auth)
+		})
+	})
+
+	Context("Creating an Auth Object For the Mock Provider", func() {
+		It("Should Sign a Token, and Create a Secret the Mock Registry Accepts", func() {
+			By("By creating a new Container Registry Auth Object for a local mock registry")
+			registry := httptest.NewServer(mockSigner.RegistryHandler())
+			defer registry.Close()
+			registryHost := strings.TrimPrefix(registry.URL, "http://")
+
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "mock",
+					Mock: containerregistryv1beta1.Mock{
+						Registry:      registryHost,
+						TokenLifetime: metav1.Duration{Duration: 10 * time.Minute},
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+
+			var dockerConfig struct {
+				Auths map[string]struct {
+					Auth string `json:"auth"`
+				} `json:"auths"`
+			}
+			Expect(json.Unmarshal(createdSecret.Data[".dockerconfigjson"], &dockerConfig)).Should(Succeed())
+			Expect(dockerConfig.Auths).Should(HaveKey(registryHost))
+
+			req, err := http.NewRequest("GET", registry.URL+"/v2/", nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Authorization", "Basic "+dockerConfig.Auths[registryHost].Auth)
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).Should(Equal(http.StatusOK))
+
+			By("By checking the status conditions and timestamps")
+			objectLookUpKey := types.NamespacedName{Name: ObjectName, Namespace: ObjectNamespace}
+			createdObject := &containerregistryv1beta1.Auth{}
+			Eventually(func() bool {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				return meta.IsStatusConditionTrue(createdObject.Status.Conditions, ConditionReady)
+			}, timeout, interval).Should(BeTrue())
+			Expect(meta.IsStatusConditionTrue(createdObject.Status.Conditions, ConditionTokenIssued)).Should(BeTrue())
+			Expect(meta.IsStatusConditionTrue(createdObject.Status.Conditions, ConditionSecretSynced)).Should(BeTrue())
+			Expect(meta.IsStatusConditionFalse(createdObject.Status.Conditions, ConditionDegraded)).Should(BeTrue())
+			Expect(createdObject.Status.ObservedGeneration).Should(Equal(createdObject.Generation))
+			Expect(createdObject.Status.SecretRef).Should(Equal(&v1.SecretReference{Name: SecretName, Namespace: ObjectNamespace}))
+			Expect(createdObject.Status.ExpirationTime).ShouldNot(BeNil())
+			Expect(createdObject.Status.LastRefreshTime).ShouldNot(BeNil())
+			Expect(createdObject.Status.NextRefreshTime.Time).Should(BeTemporally("<", createdObject.Status.ExpirationTime.Time))
+
+			By("By checking the events recorded on the Auth")
+			Eventually(func() []string {
+				return eventReasons(createdObject)
+			}, timeout, interval).Should(ContainElements(EventTokenIssued, EventSecretCreated))
+
+			By("By checking the token expiry metrics")
+			families, err := metrics.Registry.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			var untilExpiry []float64
+			for _, family := range families {
+				if family.GetName() != "auth_token_seconds_until_expiry" {
+					continue
+				}
+				for _, metric := range family.GetMetric() {
+					labels := map[string]string{}
+					for _, label := range metric.GetLabel() {
+						labels[label.GetName()] = label.GetValue()
+					}
+					if labels["namespace"] == ObjectNamespace && labels["name"] == ObjectName && labels["registry"] == "mock" {
+						untilExpiry = append(untilExpiry, metric.GetGauge().GetValue())
+					}
+				}
+			}
+			Expect(untilExpiry).Should(HaveLen(1))
+			Expect(untilExpiry[0]).Should(BeNumerically("~", 600, 60))
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+		})
+	})
+
+	Context("Creating Auth Objects with a Rotation Policy", func() {
+		It("Should Schedule the Refresh from the Credentials' Own Expiry", func() {
+			By("By creating a new Container Registry Auth Object refreshing at half the token's lifetime")
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "mock",
+					Mock: containerregistryv1beta1.Mock{
+						Registry:      "registry.local",
+						TokenLifetime: metav1.Duration{Duration: 10 * time.Minute},
+					},
+					Rotation: &containerregistryv1beta1.Rotation{
+						RefreshBefore: "50%",
+					},
+				},
+			}
+
+			k8sClient.Delete(ctx, Auth)
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			objectLookUpKey := types.NamespacedName{Name: ObjectName, Namespace: ObjectNamespace}
+			createdObject := &containerregistryv1beta1.Auth{}
+			Eventually(func() *metav1.Time {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				return createdObject.Status.NextRefreshTime
+			}, timeout, interval).ShouldNot(BeNil())
+			Expect(createdObject.Status.NextRefreshTime.Time).Should(BeTemporally("~", createdObject.Status.LastRefreshTime.Add(5*time.Minute), 5*time.Second))
+			k8sClient.Delete(ctx, Auth)
+
+			By("By creating a new Container Registry Auth Object whose token outlives the Kubernetes token")
+			Auth.ResourceVersion = ""
+			Auth.Spec.Mock.TokenLifetime = metav1.Duration{Duration: 4 * time.Hour}
+			Auth.Spec.Rotation = &containerregistryv1beta1.Rotation{RefreshBefore: "10m"}
+			Eventually(func() error {
+				return k8sClient.Create(ctx, Auth)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() *metav1.Time {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				return createdObject.Status.NextRefreshTime
+			}, timeout, interval).ShouldNot(BeNil())
+			Expect(createdObject.Status.NextRefreshTime.Time).Should(BeTemporally("~", createdObject.Status.ExpirationTime.Add(-10*time.Minute), 5*time.Second))
+
+			k8sClient.Delete(ctx, Auth)
+		})
+	})
+
+	Context("Reconciling an Auth Object whose Secret is Still Fresh", func() {
+		It("Should Reuse the Credentials Until the Spec Changes", func() {
+			By("By creating a new Container Registry Auth Object for the mock provider")
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "mock",
+					Mock: containerregistryv1beta1.Mock{
+						Registry:      "registry.local",
+						TokenLifetime: metav1.Duration{Duration: 10 * time.Minute},
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(func() string {
+				_ = k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return createdSecret.Annotations[kubernetes.SpecHashAnnotation]
+			}, timeout, interval).ShouldNot(BeEmpty())
+			Expect(createdSecret.Annotations).Should(HaveKeyWithValue(kubernetes.ProviderAnnotation, "mock"))
+			Expect(createdSecret.Annotations).Should(HaveKey(kubernetes.ExpiryAnnotation))
+			issuedAt := createdSecret.Annotations[kubernetes.IssuedAtAnnotation]
+			dockerConfig := string(createdSecret.Data[".dockerconfigjson"])
+
+			By("By triggering a reconcile with an AuthPolicy that allows everything")
+			AuthPolicy := &containerregistryv1beta1.AuthPolicy{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "AuthPolicy",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "allow-all",
+				},
+			}
+			Expect(k8sClient.Create(ctx, AuthPolicy)).Should(Succeed())
+
+			objectLookUpKey := types.NamespacedName{Name: ObjectName, Namespace: ObjectNamespace}
+			createdObject := &containerregistryv1beta1.Auth{}
+			Eventually(func() string {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				condition := meta.FindStatusCondition(createdObject.Status.Conditions, ConditionTokenIssued)
+				if condition == nil {
+					return ""
+				}
+				return condition.Reason
+			}, timeout, interval).Should(Equal("CredentialsReused"))
+			Expect(k8sClient.Get(ctx, secretLookUpKey, createdSecret)).Should(Succeed())
+			Expect(createdSecret.Annotations[kubernetes.IssuedAtAnnotation]).Should(Equal(issuedAt))
+			Expect(string(createdSecret.Data[".dockerconfigjson"])).Should(Equal(dockerConfig))
+			k8sClient.Delete(ctx, AuthPolicy)
+
+			By("By changing the spec")
+			Expect(k8sClient.Get(ctx, objectLookUpKey, createdObject)).Should(Succeed())
+			createdObject.Spec.Mock.TokenLifetime = metav1.Duration{Duration: 20 * time.Minute}
+			Expect(k8sClient.Update(ctx, createdObject)).Should(Succeed())
+
+			Eventually(func() string {
+				_ = k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return string(createdSecret.Data[".dockerconfigjson"])
+			}, timeout, interval).ShouldNot(Equal(dockerConfig))
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+		})
+	})
+
+	Context("Reconciling an Auth Object with a Registry that Keeps Failing", func() {
+		It("Should Keep the Credentials of the Other Registries Until the Retry is Due", func() {
+			By("By creating a new Container Registry Auth Object for the mock provider and an unavailable token endpoint")
+			var exchanges atomic.Int32
+			tokenEndpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				exchanges.Add(1)
+				w.Header().Set("Retry-After", "3600")
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer tokenEndpoint.Close()
+
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "quay",
+					Registries: []containerregistryv1beta1.Registry{
+						{
+							Name:              "mock",
+							ContainerRegistry: "mock",
+							Mock: containerregistryv1beta1.Mock{
+								Registry: "registry.local",
+							},
+						},
+						{
+							Name:              "unavailable",
+							ContainerRegistry: "tokenExchange",
+							TokenExchange: containerregistryv1beta1.TokenExchange{
+								TokenEndpoint: tokenEndpoint.URL,
+								Registries:    []string{"registry.example.com"},
+							},
+						},
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			objectLookUpKey := types.NamespacedName{Name: ObjectName, Namespace: ObjectNamespace}
+			createdObject := &containerregistryv1beta1.Auth{}
+			Eventually(func() *containerregistryv1beta1.RetryStatus {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				return createdObject.Status.Retry
+			}, timeout, interval).ShouldNot(BeNil())
+			Expect(createdObject.Status.Retry.NextRetryTime.Time).Should(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+
+			Expect(k8sClient.Get(ctx, secretLookUpKey, createdSecret)).Should(Succeed())
+			issuedAt := createdSecret.Annotations[kubernetes.IssuedAtAnnotation]
+			dockerConfig := string(createdSecret.Data[".dockerconfigjson"])
+			attempts := exchanges.Load()
+
+			By("By triggering a reconcile with an AuthPolicy that allows everything")
+			AuthPolicy := &containerregistryv1beta1.AuthPolicy{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "AuthPolicy",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "allow-all",
+				},
+			}
+			Expect(k8sClient.Create(ctx, AuthPolicy)).Should(Succeed())
+
+			Consistently(func() string {
+				_ = k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return createdSecret.Annotations[kubernetes.IssuedAtAnnotation] + string(createdSecret.Data[".dockerconfigjson"])
+			}, 5*time.Second, interval).Should(Equal(issuedAt + dockerConfig))
+			Expect(exchanges.Load()).Should(Equal(attempts))
+			k8sClient.Delete(ctx, AuthPolicy)
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+		})
+	})
+
+	Context("Reconciling an Auth Object with an Unavailable Registry", func() {
+		newUnavailableAuth := func(tokenEndpoint string) *containerregistryv1beta1.Auth {
+			return &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "tokenExchange",
+					TokenExchange: containerregistryv1beta1.TokenExchange{
+						TokenEndpoint: tokenEndpoint,
+						Registries:    []string{"registry.example.com"},
+					},
+				},
+			}
+		}
+
+		It("Should Retry with Exponential Backoff", func() {
+			By("By creating a new Container Registry Auth Object for a token endpoint that is unavailable")
+			var mutex sync.Mutex
+			var exchanges []time.Time
+			tokenEndpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mutex.Lock()
+				exchanges = append(exchanges, time.Now())
+				mutex.Unlock()
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer tokenEndpoint.Close()
+
+			Auth := newUnavailableAuth(tokenEndpoint.URL)
+			k8sClient.Delete(ctx, Auth)
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			By("By checking the second attempt waits for the first delay")
+			objectLookUpKey := types.NamespacedName{Name: ObjectName, Namespace: ObjectNamespace}
+			createdObject := &containerregistryv1beta1.Auth{}
+			Eventually(func() int32 {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				if createdObject.Status.Retry == nil {
+					return 0
+				}
+				return createdObject.Status.Retry.Attempts
+			}, timeout+retryBaseDelay, interval).Should(Equal(int32(2)))
+			Expect(createdObject.Status.Retry.Classification).Should(Equal(containerregistryv1beta1.RetryTransient))
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			Expect(len(exchanges)).Should(Equal(2))
+			Expect(exchanges[1].Sub(exchanges[0])).Should(BeNumerically(">=", retryBaseDelay))
+
+			By("By checking the next attempt is scheduled after twice the delay")
+			Expect(createdObject.Status.Retry.NextRetryTime.Sub(exchanges[1])).Should(BeNumerically("~", 2*retryBaseDelay, retryBaseDelay))
+
+			k8sClient.Delete(ctx, Auth)
+		})
+
+		It("Should Wait for the Retry-After the Registry Asked For", func() {
+			By("By creating a new Container Registry Auth Object for a token endpoint that asks to retry in an hour")
+			var exchanges atomic.Int32
+			tokenEndpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				exchanges.Add(1)
+				w.Header().Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+				w.WriteHeader(http.StatusTooManyRequests)
+			}))
+			defer tokenEndpoint.Close()
+
+			Auth := newUnavailableAuth(tokenEndpoint.URL)
+			k8sClient.Delete(ctx, Auth)
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			objectLookUpKey := types.NamespacedName{Name: ObjectName, Namespace: ObjectNamespace}
+			createdObject := &containerregistryv1beta1.Auth{}
+			Eventually(func() *containerregistryv1beta1.RetryStatus {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				return createdObject.Status.Retry
+			}, timeout, interval).ShouldNot(BeNil())
+			Expect(createdObject.Status.Retry.Classification).Should(Equal(containerregistryv1beta1.RetryTransient))
+			Expect(createdObject.Status.Retry.NextRetryTime.Time).Should(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+
+			By("By checking the registry is not called again before then")
+			Consistently(exchanges.Load, retryBaseDelay*2, interval).Should(Equal(int32(1)))
+
+			k8sClient.Delete(ctx, Auth)
+		})
+	})
+
+	Context("Editing or Deleting the Secret of an Auth Object", func() {
+		It("Should Write Fresh Credentials to the Secret Straight Away", func() {
+			By("By creating a new Container Registry Auth Object for the mock provider")
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "mock",
+					Mock: containerregistryv1beta1.Mock{
+						Registry:      "registry.local",
+						TokenLifetime: metav1.Duration{Duration: 10 * time.Minute},
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(func() string {
+				_ = k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return createdSecret.Annotations[kubernetes.DataHashAnnotation]
+			}, timeout, interval).ShouldNot(BeEmpty())
+			Expect(createdSecret.Labels).Should(HaveKeyWithValue(kubernetes.ManagedLabel, "true"))
+
+			By("By editing the secret")
+			createdSecret.Data[".dockerconfigjson"] = []byte(`{"auths":{}}`)
+			Expect(k8sClient.Update(ctx, createdSecret)).Should(Succeed())
+
+			Eventually(func() string {
+				_ = k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return string(createdSecret.Data[".dockerconfigjson"])
+			}, timeout, interval).Should(ContainSubstring("registry.local"))
+			Expect(createdSecret.Annotations[kubernetes.DataHashAnnotation]).Should(Equal(kubernetes.DockerConfigHash(createdSecret.Data[".dockerconfigjson"])))
+
+			By("By removing the managed label, which drops the secret from the cache")
+			delete(createdSecret.Labels, kubernetes.ManagedLabel)
+			Expect(k8sClient.Update(ctx, createdSecret)).Should(Succeed())
+
+			Eventually(func() map[string]string {
+				_ = k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return createdSecret.Labels
+			}, timeout, interval).Should(HaveKeyWithValue(kubernetes.ManagedLabel, "true"))
+
+			By("By deleting the secret")
+			deletedUID := createdSecret.UID
+			Expect(k8sClient.Delete(ctx, createdSecret)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return err == nil && createdSecret.UID != deletedUID
+			}, timeout, interval).Should(BeTrue())
+			Expect(string(createdSecret.Data[".dockerconfigjson"])).Should(ContainSubstring("registry.local"))
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+		})
+	})
+
+	Context("Creating an Auth Object For Several Registries", func() {
+		It("Should Render Every Registry into One Secret, and Report Each Registry's Status", func() {
+			By("By creating a new Container Registry Auth Object with two mock registries and an unknown plugin")
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "quay",
+					Registries: []containerregistryv1beta1.Registry{
+						{
+							Name:              "short",
+							ContainerRegistry: "mock",
+							Mock: containerregistryv1beta1.Mock{
+								Registry:      "short.registry.local",
+								TokenLifetime: metav1.Duration{Duration: 10 * time.Minute},
+							},
+						},
+						{
+							Name:              "long",
+							Audiences:         []string{"long.registry.local"},
+							ContainerRegistry: "mock",
+							Mock: containerregistryv1beta1.Mock{
+								Registry:      "long.registry.local",
+								TokenLifetime: metav1.Duration{Duration: time.Hour},
+							},
+						},
+						{
+							Name:              "unknown",
+							ContainerRegistry: "plugin",
+							Plugin: containerregistryv1beta1.Plugin{
+								Name: "unknown",
+							},
+						},
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+
+			var dockerConfig struct {
+				Auths map[string]struct {
+					Auth string `json:"auth"`
+				} `json:"auths"`
+			}
+			Expect(json.Unmarshal(createdSecret.Data[".dockerconfigjson"], &dockerConfig)).Should(Succeed())
+			Expect(dockerConfig.Auths).Should(HaveKey("short.registry.local"))
+			Expect(dockerConfig.Auths).Should(HaveKey("long.registry.local"))
+			Expect(dockerConfig.Auths).Should(HaveLen(2))
+
+			objectLookUpKey := types.NamespacedName{Name: ObjectName, Namespace: ObjectNamespace}
+			createdObject := &containerregistryv1beta1.Auth{}
+			Eventually(func() int {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				return len(createdObject.Status.Registries)
+			}, timeout, interval).Should(Equal(3))
+			Expect(createdObject.Status.Registries[0].Error).Should(BeEmpty())
+			Expect(createdObject.Status.Registries[1].Error).Should(BeEmpty())
+			Expect(createdObject.Status.Registries[2].Error).Should(Equal("plugin 'unknown' is not allowed by the controller's plugin config"))
+			Expect(createdObject.Status.TokenExpiration).Should(Equal(createdObject.Status.Registries[0].TokenExpiration))
+			Expect(createdObject.Status.Error).Should(Equal("unknown: plugin 'unknown' is not allowed by the controller's plugin config"))
+			Expect(createdObject.Status.Registries[0].Hosts).Should(Equal([]string{"short.registry.local"}))
+			Expect(createdObject.Status.Retry).ShouldNot(BeNil())
+			Expect(createdObject.Status.Retry.Classification).Should(Equal(containerregistryv1beta1.RetryPermanent))
+
+			By("By breaking a registry, whose previous credentials are kept until they expire")
+			Expect(k8sClient.Get(ctx, objectLookUpKey, createdObject)).Should(Succeed())
+			createdObject.Spec.Registries[1].ContainerRegistry = "plugin"
+			createdObject.Spec.Registries[1].Plugin = containerregistryv1beta1.Plugin{Name: "unknown"}
+			Expect(k8sClient.Update(ctx, createdObject)).Should(Succeed())
+
+			Eventually(func() string {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				if len(createdObject.Status.Registries) != 3 {
+					return ""
+				}
+				return createdObject.Status.Registries[1].Error
+			}, timeout, interval).Should(Equal("plugin 'unknown' is not allowed by the controller's plugin config"))
+			Expect(createdObject.Status.Registries[1].Hosts).Should(Equal([]string{"long.registry.local"}))
+			Expect(createdObject.Status.Registries[1].ExpirationTime).ShouldNot(BeNil())
+			Expect(k8sClient.Get(ctx, secretLookUpKey, createdSecret)).Should(Succeed())
+			Expect(json.Unmarshal(createdSecret.Data[".dockerconfigjson"], &dockerConfig)).Should(Succeed())
+			Expect(dockerConfig.Auths).Should(HaveKey("long.registry.local"))
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+		})
+	})
+
+	Context("Creating an Auth Object Linked to Service Accounts", func() {
+		It("Should Reference the Secret from the Service Account, and Remove the Reference when the Auth is Deleted", func() {
+			By("By creating a new Container Registry Auth Object linked to its own service account")
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "mock",
+					Mock: containerregistryv1beta1.Mock{
+						Registry:      "registry.local",
+						TokenLifetime: metav1.Duration{Duration: 10 * time.Minute},
+					},
+					LinkToServiceAccounts: &containerregistryv1beta1.LinkToServiceAccounts{
+						Tekton: true,
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			serviceAccountLookUpKey := types.NamespacedName{Name: ServiceAccount, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+			serviceAccount := &v1.ServiceAccount{}
+
+			linked := func() bool {
+				if err := k8sClient.Get(ctx, serviceAccountLookUpKey, serviceAccount); err != nil {
+					return false
+				}
+				for _, reference := range serviceAccount.ImagePullSecrets {
+					if reference.Name == SecretName {
+						return true
+					}
+				}
+				return false
+			}
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(linked, timeout, interval).Should(BeTrue())
+			Expect(serviceAccount.Secrets).Should(ContainElement(HaveField("Name", SecretName)))
+			Expect(k8sClient.Get(ctx, secretLookUpKey, createdSecret)).Should(Succeed())
+			Expect(createdSecret.Annotations).Should(HaveKeyWithValue("tekton.dev/docker-0", "https://registry.local"))
+
+			By("By deleting the Container Registry Auth Object")
+			Expect(k8sClient.Delete(ctx, Auth)).Should(Succeed())
+			Eventually(linked, timeout, interval).Should(BeFalse())
+			Expect(serviceAccount.Secrets).ShouldNot(ContainElement(HaveField("Name", SecretName)))
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: ObjectName, Namespace: ObjectNamespace}, &containerregistryv1beta1.Auth{})
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			k8sClient.Delete(ctx, createdSecret)
+		})
+	})
+
+	Context("Creating an Auth Object Denied by an AuthPolicy", func() {
+		It("Should not Create the Secret, and Report the Violation as a Condition", func() {
+			By("By creating an AuthPolicy denying the mock registry, and an Auth Object using it")
+			AuthPolicy := &containerregistryv1beta1.AuthPolicy{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "AuthPolicy",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "deny-mock",
+				},
+				Spec: containerregistryv1beta1.AuthPolicySpec{
+					ContainerRegistries: containerregistryv1beta1.PatternRule{
+						Deny: []string{"mock"},
+					},
+				},
+			}
+
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    ServiceAccount,
+					Audiences:         Audiences,
+					ContainerRegistry: "mock",
+					Mock: containerregistryv1beta1.Mock{
+						Registry:      "registry.local",
+						TokenLifetime: metav1.Duration{Duration: 10 * time.Minute},
+					},
+				},
+			}
+
+			secretLookUpKey := types.NamespacedName{Name: SecretName, Namespace: ObjectNamespace}
+			createdSecret := &v1.Secret{}
+
+			k8sClient.Delete(ctx, AuthPolicy)
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+			k8sClient.Delete(ctx, createdSecret)
+
+			Expect(k8sClient.Create(ctx, AuthPolicy)).Should(Succeed())
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			objectLookUpKey := types.NamespacedName{Name: ObjectName, Namespace: ObjectNamespace}
+			createdObject := &containerregistryv1beta1.Auth{}
+			Eventually(func() bool {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				return meta.IsStatusConditionFalse(createdObject.Status.Conditions, ConditionPolicyCompliant)
+			}, timeout, interval).Should(BeTrue())
+			Expect(createdObject.Status.Error).Should(ContainSubstring("AuthPolicy 'deny-mock' does not allow containerRegistry 'mock'"))
+
+			Consistently(func() bool {
+				err := k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return apierrors.IsNotFound(err)
+			}, time.Second*2, interval).Should(BeTrue())
+
+			By("By deleting the AuthPolicy")
+			Expect(k8sClient.Delete(ctx, AuthPolicy)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, secretLookUpKey, createdSecret)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+
+			k8sClient.Delete(ctx, Auth)
+			k8sClient.Delete(ctx, createdSecret)
+		})
+	})
+
+	Context("Creating an Auth Object for a Missing Service Account", func() {
+		It("Should Record a ServiceAccountMissing Event, and Only Retry Once the Spec Changes", func() {
+			By("By creating a new Container Registry Auth Object for a service account that does not exist")
+			Auth := &containerregistryv1beta1.Auth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "Auth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ObjectName,
+					Namespace: ObjectNamespace,
+				},
+				Spec: containerregistryv1beta1.AuthSpec{
+					SecretName:        SecretName,
+					ServiceAccount:    "does-not-exist",
+					Audiences:         Audiences,
+					ContainerRegistry: "mock",
+					Mock: containerregistryv1beta1.Mock{
+						Registry:      "registry.local",
+						TokenLifetime: metav1.Duration{Duration: 10 * time.Minute},
+					},
+				},
+			}
+
+			k8sClient.Delete(ctx, Auth)
+			Expect(k8sClient.Create(ctx, Auth)).Should(Succeed())
+
+			Eventually(func() []string {
+				return eventReasons(Auth)
+			}, timeout, interval).Should(ContainElement(EventServiceAccountMissing))
+
+			By("By checking the failure is classified as permanent")
+			objectLookUpKey := types.NamespacedName{Name: ObjectName, Namespace: ObjectNamespace}
+			createdObject := &containerregistryv1beta1.Auth{}
+			Eventually(func() *containerregistryv1beta1.RetryStatus {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				return createdObject.Status.Retry
+			}, timeout, interval).ShouldNot(BeNil())
+			Expect(createdObject.Status.Retry.Classification).Should(Equal(containerregistryv1beta1.RetryPermanent))
+			Expect(createdObject.Status.Retry.NextRetryTime).Should(BeNil())
+			Expect(createdObject.Status.NextRefreshTime).Should(BeNil())
+			Consistently(func() int32 {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				return createdObject.Status.Retry.Attempts
+			}, time.Second*2, interval).Should(Equal(int32(1)))
+
+			By("By changing the spec, which starts the retries over")
+			createdObject.Spec.Audiences = []string{"changed"}
+			Expect(k8sClient.Update(ctx, createdObject)).Should(Succeed())
+			generation := createdObject.Generation
+			Eventually(func() int64 {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				return createdObject.Status.ObservedGeneration
+			}, timeout, interval).Should(Equal(generation))
+			Expect(createdObject.Status.Retry.Attempts).Should(Equal(int32(1)))
+
+			k8sClient.Delete(ctx, Auth)
+		})
+	})
+
 })
@@ -0,0 +1,44 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/kubernetes"
+)
+
+// CacheOptions Limits the Manager's Cache of Secrets, Backing the Watches of the Controllers, to the Image Pull Secrets they Write,
+// Rather than Every Secret in the Cluster
+func CacheOptions() cache.Options {
+	return cache.Options{
+		ByObject: map[client.Object]cache.ByObject{
+			&coreV1.Secret{}: {Label: labels.SelectorFromSet(labels.Set{kubernetes.ManagedLabel: "true"})},
+		},
+	}
+}
+
+// ClientOptions Reads Secrets from the API Server, as the Secrets Referenced by Auths, and Secrets Written Before
+// they were Labelled, are not in the Cache
+func ClientOptions() client.Options {
+	return client.Options{
+		Cache: &client.CacheOptions{DisableFor: []client.Object{&coreV1.Secret{}}},
+	}
+}
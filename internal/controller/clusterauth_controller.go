@@ -0,0 +1,348 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/kubernetes"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// Label on the Image Pull Secrets Written by a ClusterAuth, Set to the ClusterAuth's UID
+const ClusterAuthLabel = "containerregistry.arthurvardevanyan.com/cluster-auth"
+
+// ClusterAuthReconciler reconciles a ClusterAuth object
+type ClusterAuthReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Providers available to Spec.ContainerRegistry, defaults to DefaultProviders
+	Providers *provider.Registry
+	// The Controller's Namespace, Credentials are Minted with the Service Accounts and Config Maps in it
+	Namespace string
+	// Records Token Issuance and Failures on the ClusterAuth, defaults to the Manager's
+	Recorder record.EventRecorder
+
+	retries *retryRateLimiter
+}
+
+// updateClusterAuthObject Updates the ClusterAuth's Status, and Requeues it After requeueAfter,
+// or Returns retryErr so the Workqueue Retries it
+func updateClusterAuthObject(r *ClusterAuthReconciler, reconcilerContext context.Context, clusterAuth containerregistryv1beta1.ClusterAuth, requeueAfter time.Duration, retryErr error) (ctrl.Result, error) {
+	finalizeStatus(&clusterAuth.Status.AuthStatus, clusterAuth.Generation, requeueAfter)
+
+	if err := r.Status().Update(reconcilerContext, &clusterAuth); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to update Cluster Auth status: %w", err)
+	} else if retryErr != nil {
+		return ctrl.Result{}, retryErr
+	} else {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+}
+
+// +kubebuilder:rbac:groups=containerregistry.arthurvardevanyan.com,resources=clusterauths,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=containerregistry.arthurvardevanyan.com,resources=clusterauths/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=containerregistry.arthurvardevanyan.com,resources=clusterauths/finalizers,verbs=update
+
+// CUSTOM RBAC
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Reconcile Mints the ClusterAuth's Credentials Once, and Writes them to Every Namespace Matching Spec.NamespaceSelector.
+// Secrets in Namespaces that No Longer Match are Deleted.
+func (r *ClusterAuthReconciler) Reconcile(reconcilerContext context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(reconcilerContext)
+	log.V(1).Info(req.Name)
+
+	var err, exchangeErr, retryErr error
+	var error string
+
+	reconcilerContext, span := tracer.Start(reconcilerContext, "ClusterAuth.Reconcile", trace.WithAttributes(attribute.String("clusterauth.name", req.Name)))
+	defer span.End()
+
+	// Incept Object
+	var clusterAuth containerregistryv1beta1.ClusterAuth
+	if err = r.Get(reconcilerContext, req.NamespacedName, &clusterAuth); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			log.V(1).Info("Cluster Auth Object Not Found or No Longer Exists!")
+			tokenExpiry.delete("", req.Name)
+			return ctrl.Result{}, nil
+		} else {
+			log.Error(err, "Unable to fetch Cluster Auth Object")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+	}
+
+	resetRetry(&clusterAuth.Status.AuthStatus, clusterAuth.Generation)
+
+	// Exchange the Credentials as an Auth in the Controller's Namespace
+	containerRegistryAuth := clusterAuthAsAuth(&clusterAuth, r.Namespace)
+	var dockerConfig string
+	var metadata kubernetes.PullSecretMetadata
+	hash := specHash(reconcilerContext, r.Client, r.Providers, containerRegistryAuth)
+	// Reuse the Credentials in the Image Pull Secrets While they are Fresh, so Restarts do not Mint New Ones
+	if existingSecret, existingMetadata, fresh := r.freshImagePullSecret(reconcilerContext, &clusterAuth, hash); fresh {
+		log.V(1).Info("Reusing Fresh Credentials", "issuedAt", existingMetadata.IssuedAt, "expiration", existingMetadata.Expiration)
+		reuseCredentials(&clusterAuth, containerRegistryAuth, existingMetadata)
+		dockerConfig = string(existingSecret.Data[".dockerconfigjson"])
+		metadata = existingMetadata
+	} else {
+		// Registries that Fail Keep their Previous Credentials Until they Expire
+		var imagePullSecretAuths kubernetes.ImagePullSecretAuths
+		var expiration time.Time
+		imagePullSecretAuths, expiration, exchangeErr = exchangeRegistries(reconcilerContext, r.Client, r.Providers, r.Recorder, &clusterAuth, containerRegistryAuth, 0,
+			r.previousCredentials(reconcilerContext, &clusterAuth))
+		if imagePullSecretAuths == nil {
+			clusterAuth.Status.AuthStatus = containerRegistryAuth.Status
+			return updateClusterAuthObject(r, reconcilerContext, clusterAuth, 0, r.retries.scheduleRetry(req, &clusterAuth.Status.AuthStatus, exchangeErr, 0))
+		}
+		dockerConfig = imagePullSecretAuths.String()
+		metadata = pullSecretMetadata(containerRegistryAuth, expiration, hash)
+	}
+	clusterAuth.Status.AuthStatus = containerRegistryAuth.Status
+
+	namespaceSelector, err := metaV1.LabelSelectorAsSelector(&clusterAuth.Spec.NamespaceSelector)
+	if err != nil {
+		error = "Invalid Namespace Selector"
+		clusterAuth.Status.Error = err.Error()
+		setCondition(&clusterAuth.Status.AuthStatus, clusterAuth.Generation, ConditionSecretSynced, metaV1.ConditionFalse, "InvalidNamespaceSelector", err.Error())
+		log.Error(err, error)
+		return updateClusterAuthObject(r, reconcilerContext, clusterAuth, 0, r.retries.scheduleRetry(req, &clusterAuth.Status.AuthStatus, permanent(err), 0))
+	}
+
+	var namespaces coreV1.NamespaceList
+	if err = r.List(reconcilerContext, &namespaces, client.MatchingLabelsSelector{Selector: namespaceSelector}); err != nil {
+		error = "Unable to List Namespaces"
+		clusterAuth.Status.Error = error
+		setCondition(&clusterAuth.Status.AuthStatus, clusterAuth.Generation, ConditionSecretSynced, metaV1.ConditionFalse, "NamespaceListFailed", err.Error())
+		log.Error(err, error)
+		return updateClusterAuthObject(r, reconcilerContext, clusterAuth, 0, r.retries.scheduleRetry(req, &clusterAuth.Status.AuthStatus, err, 0))
+	}
+
+	var ownerRef = metaV1.OwnerReference{
+		APIVersion:         clusterAuth.APIVersion,
+		Kind:               clusterAuth.Kind,
+		Name:               clusterAuth.Name,
+		UID:                clusterAuth.UID,
+		Controller:         BoolPointer(true),
+		BlockOwnerDeletion: BoolPointer(true),
+	}
+	ownerReference := []metaV1.OwnerReference{ownerRef}
+
+	// Create Image Pull Secrets
+	matchingNamespaces := map[string]bool{}
+	var failedNamespaces, conflictingNamespaces []string
+	clusterAuth.Status.Namespaces = nil
+	for _, namespace := range namespaces.Items {
+		if namespace.DeletionTimestamp != nil {
+			continue
+		}
+		matchingNamespaces[namespace.Name] = true
+
+		namespaceStatus := containerregistryv1beta1.NamespaceStatus{Name: namespace.Name, Synced: true}
+
+		// Leave Secrets Belonging to Users or Auths Alone
+		existingSecret := &coreV1.Secret{}
+		if r.Get(reconcilerContext, types.NamespacedName{Name: clusterAuth.Spec.SecretName, Namespace: namespace.Name}, existingSecret) == nil && !metaV1.IsControlledBy(existingSecret, &clusterAuth) {
+			namespaceStatus.Synced = false
+			namespaceStatus.Error = "A Secret with the Same Name, not Managed by this Cluster Auth, Already Exists"
+			conflictingNamespaces = append(conflictingNamespaces, namespace.Name)
+			clusterAuth.Status.Namespaces = append(clusterAuth.Status.Namespaces, namespaceStatus)
+			continue
+		}
+
+		imagePullSecret := kubernetes.ImagePullSecretObject(clusterAuth.Spec.SecretName, namespace.Name, dockerConfig, ownerReference, metadata)
+		imagePullSecret.Labels[ClusterAuthLabel] = string(clusterAuth.UID)
+		if _, err = writeImagePullSecret(reconcilerContext, r.Client, imagePullSecret); err != nil {
+			error = "Unable to Create Image Pull Secret"
+			namespaceStatus.Synced = false
+			namespaceStatus.Error = error
+			failedNamespaces = append(failedNamespaces, namespace.Name)
+			log.Error(err, error, "namespace", namespace.Name)
+		}
+		clusterAuth.Status.Namespaces = append(clusterAuth.Status.Namespaces, namespaceStatus)
+	}
+	clusterAuth.Status.SecretRef = &coreV1.SecretReference{Name: clusterAuth.Spec.SecretName}
+	message, namespacesErr := namespaceSyncError(failedNamespaces, conflictingNamespaces)
+	if namespacesErr != nil {
+		reason := "NamespacesNotSynced"
+		if len(failedNamespaces) == 0 {
+			reason = "SecretConflict"
+		}
+		if clusterAuth.Status.Error != "" {
+			clusterAuth.Status.Error += "; "
+		}
+		clusterAuth.Status.Error += message
+		setCondition(&clusterAuth.Status.AuthStatus, clusterAuth.Generation, ConditionSecretSynced, metaV1.ConditionFalse, reason, message)
+	} else {
+		setCondition(&clusterAuth.Status.AuthStatus, clusterAuth.Generation, ConditionSecretSynced, metaV1.ConditionTrue, "SecretWritten", fmt.Sprintf("The Image Pull Secret is Up to Date in %d Namespaces", len(clusterAuth.Status.Namespaces)))
+	}
+
+	// Clean Up Secrets in Namespaces that No Longer Match, or Left Behind by a Renamed Secret
+	var imagePullSecrets coreV1.SecretList
+	if err = r.List(reconcilerContext, &imagePullSecrets, client.MatchingLabels{ClusterAuthLabel: string(clusterAuth.UID)}); err != nil {
+		error = "Unable to List Image Pull Secrets"
+		clusterAuth.Status.Error = error
+		setCondition(&clusterAuth.Status.AuthStatus, clusterAuth.Generation, ConditionSecretSynced, metaV1.ConditionFalse, "SecretListFailed", err.Error())
+		log.Error(err, error)
+		return updateClusterAuthObject(r, reconcilerContext, clusterAuth, 0, r.retries.scheduleRetry(req, &clusterAuth.Status.AuthStatus, err, 0))
+	}
+	for i := range imagePullSecrets.Items {
+		imagePullSecret := &imagePullSecrets.Items[i]
+		if (matchingNamespaces[imagePullSecret.Namespace] && imagePullSecret.Name == clusterAuth.Spec.SecretName) || !metaV1.IsControlledBy(imagePullSecret, &clusterAuth) {
+			continue
+		}
+		if err = r.Delete(reconcilerContext, imagePullSecret); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "Unable to Delete Image Pull Secret", "namespace", imagePullSecret.Namespace)
+		}
+	}
+
+	// Retry Registries and Namespaces that Failed, Without Waiting for the Next Refresh
+	requeueAfter := refreshAfter(clusterAuth.Spec.Rotation, metadata.IssuedAt, metadata.Expiration)
+	if failure := errors.Join(exchangeErr, namespacesErr); failure != nil {
+		retryErr = r.retries.scheduleRetry(req, &clusterAuth.Status.AuthStatus, failure, requeueAfter)
+	} else if untilRetry, pending := pendingRetry(&clusterAuth.Status.AuthStatus, clusterAuth.Generation); pending {
+		// Reused Credentials Keep the Retry of the Registries that Failed
+		requeueAfter = min(requeueAfter, untilRetry)
+	} else {
+		clusterAuth.Status.Retry = nil
+	}
+	return updateClusterAuthObject(r, reconcilerContext, clusterAuth, requeueAfter, retryErr)
+}
+
+// namespaceSyncError Describes the Namespaces the Image Pull Secret could not be Written to.
+// Write Failures are Retried with Backoff, Conflicts are Permanent Until the Other Secret is Removed.
+func namespaceSyncError(failedNamespaces []string, conflictingNamespaces []string) (string, error) {
+	var messages []string
+	var errs []error
+	if len(failedNamespaces) > 0 {
+		message := fmt.Sprintf("Unable to Write the Image Pull Secret to %s", strings.Join(failedNamespaces, ", "))
+		messages = append(messages, message)
+		errs = append(errs, errors.New(message))
+	}
+	if len(conflictingNamespaces) > 0 {
+		message := fmt.Sprintf("Secrets not Managed by the Cluster Auth Already Exist in %s", strings.Join(conflictingNamespaces, ", "))
+		messages = append(messages, message)
+		errs = append(errs, permanent(errors.New(message)))
+	}
+	return strings.Join(messages, "; "), errors.Join(errs...)
+}
+
+// freshImagePullSecret Returns an Image Pull Secret Written by the ClusterAuth, Holding Fresh Credentials for the Spec Hashed to hash.
+// Credentials are Only Reused when the Last Reconcile Succeeded in Every Namespace.
+func (r *ClusterAuthReconciler) freshImagePullSecret(reconcilerContext context.Context, clusterAuth *containerregistryv1beta1.ClusterAuth, hash string) (*coreV1.Secret, kubernetes.PullSecretMetadata, bool) {
+	if !credentialsReusable(&clusterAuth.Status.AuthStatus, clusterAuth.Generation) {
+		return nil, kubernetes.PullSecretMetadata{}, false
+	}
+
+	var imagePullSecrets coreV1.SecretList
+	if err := r.List(reconcilerContext, &imagePullSecrets, client.MatchingLabels{ClusterAuthLabel: string(clusterAuth.UID)}); err != nil {
+		return nil, kubernetes.PullSecretMetadata{}, false
+	}
+	for i := range imagePullSecrets.Items {
+		imagePullSecret := &imagePullSecrets.Items[i]
+		if imagePullSecret.Name != clusterAuth.Spec.SecretName {
+			continue
+		}
+		if metadata, fresh := freshCredentials(imagePullSecret, clusterAuth, clusterAuth.Spec.Rotation, hash, 0); fresh {
+			return imagePullSecret, metadata, true
+		}
+	}
+	return nil, kubernetes.PullSecretMetadata{}, false
+}
+
+// previousCredentials Returns the Credentials in One of the ClusterAuth's Image Pull Secrets
+func (r *ClusterAuthReconciler) previousCredentials(reconcilerContext context.Context, clusterAuth *containerregistryv1beta1.ClusterAuth) kubernetes.ImagePullSecretAuths {
+	var imagePullSecrets coreV1.SecretList
+	if err := r.List(reconcilerContext, &imagePullSecrets, client.MatchingLabels{ClusterAuthLabel: string(clusterAuth.UID)}); err != nil {
+		return nil
+	}
+	for i := range imagePullSecrets.Items {
+		if imagePullSecrets.Items[i].Name != clusterAuth.Spec.SecretName {
+			continue
+		}
+		if auths := previousCredentials(&imagePullSecrets.Items[i], clusterAuth); auths != nil {
+			return auths
+		}
+	}
+	return nil
+}
+
+// clusterAuthsForNamespace Requests Every ClusterAuth when a Namespace is Created, Relabelled or Deleted
+func (r *ClusterAuthReconciler) clusterAuthsForNamespace(reconcilerContext context.Context, _ client.Object) []reconcile.Request {
+	var clusterAuths containerregistryv1beta1.ClusterAuthList
+	if err := r.List(reconcilerContext, &clusterAuths); err != nil {
+		log.FromContext(reconcilerContext).Error(err, "Unable to List Cluster Auth Objects")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(clusterAuths.Items))
+	for _, clusterAuth := range clusterAuths.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&clusterAuth)})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterAuthReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Providers == nil {
+		r.Providers = DefaultProviders(nil)
+	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("clusterauth-controller")
+	}
+	r.retries = newRetryRateLimiter()
+	if err := indexReferences(mgr, &containerregistryv1beta1.ClusterAuth{}, func(object client.Object) *containerregistryv1beta1.Auth {
+		return clusterAuthAsAuth(object.(*containerregistryv1beta1.ClusterAuth), r.Namespace)
+	}); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{RateLimiter: r.retries}).
+		// Status Updates do not Trigger a Reconcile, so Retries Follow their Backoff
+		For(&containerregistryv1beta1.ClusterAuth{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Secrets Deleted or Edited in Any Namespace are Written Again
+		Owns(&coreV1.Secret{}, builder.WithPredicates(secretChangedPredicate)).
+		Watches(&coreV1.ServiceAccount{}, handler.EnqueueRequestsFromMapFunc(r.clusterAuthsReferencing(serviceAccountIndex))).
+		Watches(&coreV1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.clusterAuthsReferencing(configMapIndex))).
+		Watches(
+			&coreV1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.clusterAuthsForNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		).
+		Complete(r)
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+)
+
+var _ = Describe("ClusterAuth controller", func() {
+
+	const (
+		timeout  = time.Second * 10
+		interval = time.Millisecond * 250
+	)
+
+	var Audiences = []string{"openshift"}
+
+	var ObjectName = getEnv("OBJECT_NAME", "test")
+	var SecretName = getEnv("SECRET_NAME", "container-registry-auth-test")
+	var ServiceAccount = getEnv("SERVICE_ACCOUNT", "wif-test")
+
+	const teamLabel = "containerregistry.arthurvardevanyan.com/test-team"
+
+	Context("Creating a ClusterAuth Object For the Mock Provider", func() {
+		It("Should Write the Secret to Matching Namespaces, and Remove it When a Namespace Stops Matching", func() {
+			By("By creating namespaces and a ClusterAuth Object selecting one of them")
+			matching := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "cluster-auth-matching",
+				Labels: map[string]string{teamLabel: "true"},
+			}}
+			other := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-auth-other",
+			}}
+			for _, namespace := range []*v1.Namespace{matching, other} {
+				if err := k8sClient.Create(ctx, namespace); err != nil {
+					Expect(apierrors.IsAlreadyExists(err)).Should(BeTrue())
+				}
+			}
+
+			ClusterAuth := &containerregistryv1beta1.ClusterAuth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "ClusterAuth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: ObjectName,
+				},
+				Spec: containerregistryv1beta1.ClusterAuthSpec{
+					AuthSpec: containerregistryv1beta1.AuthSpec{
+						SecretName:        SecretName,
+						ServiceAccount:    ServiceAccount,
+						Audiences:         Audiences,
+						ContainerRegistry: "mock",
+						Mock: containerregistryv1beta1.Mock{
+							Registry:      "registry.local",
+							TokenLifetime: metav1.Duration{Duration: 10 * time.Minute},
+						},
+					},
+					NamespaceSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{teamLabel: "true"},
+					},
+				},
+			}
+
+			k8sClient.Delete(ctx, ClusterAuth)
+			Expect(k8sClient.Create(ctx, ClusterAuth)).Should(Succeed())
+
+			matchingSecretKey := types.NamespacedName{Name: SecretName, Namespace: matching.Name}
+			createdSecret := &v1.Secret{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, matchingSecretKey, createdSecret)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+			Expect(string(createdSecret.Data[".dockerconfigjson"])).Should(ContainSubstring("registry.local"))
+
+			otherSecretKey := types.NamespacedName{Name: SecretName, Namespace: other.Name}
+			Consistently(func() bool {
+				err := k8sClient.Get(ctx, otherSecretKey, &v1.Secret{})
+				return apierrors.IsNotFound(err)
+			}, time.Second*2, interval).Should(BeTrue())
+
+			objectLookUpKey := types.NamespacedName{Name: ObjectName}
+			createdObject := &containerregistryv1beta1.ClusterAuth{}
+			Eventually(func() []containerregistryv1beta1.NamespaceStatus {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				return createdObject.Status.Namespaces
+			}, timeout, interval).Should(ContainElement(containerregistryv1beta1.NamespaceStatus{Name: matching.Name, Synced: true}))
+
+			By("By relabelling the namespaces")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: other.Name}, other)).Should(Succeed())
+			other.Labels = map[string]string{teamLabel: "true"}
+			Expect(k8sClient.Update(ctx, other)).Should(Succeed())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: matching.Name}, matching)).Should(Succeed())
+			matching.Labels = map[string]string{}
+			Expect(k8sClient.Update(ctx, matching)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, otherSecretKey, &v1.Secret{})
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, matchingSecretKey, &v1.Secret{})
+				return apierrors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
+
+			k8sClient.Delete(ctx, ClusterAuth)
+			k8sClient.Delete(ctx, matching)
+			k8sClient.Delete(ctx, other)
+		})
+	})
+
+	Context("Creating a ClusterAuth Object Selecting a Namespace with a User's Secret of the Same Name", func() {
+		It("Should Leave the User's Secret Alone, and Report the Conflict", func() {
+			By("By creating a namespace holding a secret, and a ClusterAuth Object selecting it")
+			namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "cluster-auth-conflict",
+				Labels: map[string]string{teamLabel: "true"},
+			}}
+			if err := k8sClient.Create(ctx, namespace); err != nil {
+				Expect(apierrors.IsAlreadyExists(err)).Should(BeTrue())
+			}
+			userSecret := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: SecretName, Namespace: namespace.Name},
+				StringData: map[string]string{"owner": "user"},
+			}
+			k8sClient.Delete(ctx, userSecret)
+			Expect(k8sClient.Create(ctx, userSecret)).Should(Succeed())
+
+			ClusterAuth := &containerregistryv1beta1.ClusterAuth{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "containerregistry.arthurvardevanyan.com/v1beta1",
+					Kind:       "ClusterAuth",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: ObjectName,
+				},
+				Spec: containerregistryv1beta1.ClusterAuthSpec{
+					AuthSpec: containerregistryv1beta1.AuthSpec{
+						SecretName:        SecretName,
+						ServiceAccount:    ServiceAccount,
+						Audiences:         Audiences,
+						ContainerRegistry: "mock",
+						Mock: containerregistryv1beta1.Mock{
+							Registry:      "registry.local",
+							TokenLifetime: metav1.Duration{Duration: 10 * time.Minute},
+						},
+					},
+					NamespaceSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{teamLabel: "true"},
+					},
+				},
+			}
+
+			k8sClient.Delete(ctx, ClusterAuth)
+			Eventually(func() error {
+				return k8sClient.Create(ctx, ClusterAuth)
+			}, timeout, interval).Should(Succeed())
+
+			objectLookUpKey := types.NamespacedName{Name: ObjectName}
+			createdObject := &containerregistryv1beta1.ClusterAuth{}
+			Eventually(func() []containerregistryv1beta1.NamespaceStatus {
+				_ = k8sClient.Get(ctx, objectLookUpKey, createdObject)
+				return createdObject.Status.Namespaces
+			}, timeout, interval).Should(ContainElement(containerregistryv1beta1.NamespaceStatus{
+				Name:   namespace.Name,
+				Synced: false,
+				Error:  "A Secret with the Same Name, not Managed by this Cluster Auth, Already Exists",
+			}))
+
+			secret := &v1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: SecretName, Namespace: namespace.Name}, secret)).Should(Succeed())
+			Expect(secret.OwnerReferences).Should(BeEmpty())
+			Expect(secret.Labels).ShouldNot(HaveKey(ClusterAuthLabel))
+			Expect(string(secret.Data["owner"])).Should(Equal("user"))
+
+			By("By removing the namespace from the selector")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: namespace.Name}, namespace)).Should(Succeed())
+			namespace.Labels = map[string]string{}
+			Expect(k8sClient.Update(ctx, namespace)).Should(Succeed())
+			Consistently(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: SecretName, Namespace: namespace.Name}, &v1.Secret{})
+			}, time.Second*2, interval).Should(Succeed())
+
+			k8sClient.Delete(ctx, ClusterAuth)
+			k8sClient.Delete(ctx, userSecret)
+			k8sClient.Delete(ctx, namespace)
+		})
+	})
+
+	Context("Creating a ClusterAuth Object Linking Service Accounts", func() {
+		It("Should be Rejected, Since Only Auths Link Service Accounts", func() {
+			ClusterAuth := &containerregistryv1beta1.ClusterAuth{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-auth-link",
+				},
+				Spec: containerregistryv1beta1.ClusterAuthSpec{
+					AuthSpec: containerregistryv1beta1.AuthSpec{
+						SecretName:            SecretName,
+						ServiceAccount:        ServiceAccount,
+						Audiences:             Audiences,
+						ContainerRegistry:     "mock",
+						LinkToServiceAccounts: &containerregistryv1beta1.LinkToServiceAccounts{Names: []string{"default"}},
+					},
+					NamespaceSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{teamLabel: "true"},
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, ClusterAuth)
+			Expect(apierrors.IsInvalid(err)).Should(BeTrue())
+			Expect(err.Error()).Should(ContainSubstring("linkToServiceAccounts is only supported by Auths"))
+		})
+	})
+
+})
@@ -0,0 +1,86 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+)
+
+// Conditions Reported on Auths and ClusterAuths
+const (
+	// The Credentials are Issued and the Secret is Up to Date
+	ConditionReady = "Ready"
+	// Registry Credentials were Issued for at Least One Registry
+	ConditionTokenIssued = "TokenIssued"
+	// The Image Pull Secret Holds the Latest Credentials
+	ConditionSecretSynced = "SecretSynced"
+	// Part of the Auth is Failing, Status.Error Describes the Failures
+	ConditionDegraded = "Degraded"
+	// The Auth Complies with the AuthPolicies of its Namespace
+	ConditionPolicyCompliant = "PolicyCompliant"
+)
+
+// setCondition Sets the Condition, Observed at the Status' Generation
+func setCondition(status *containerregistryv1beta1.AuthStatus, generation int64, conditionType string, conditionStatus metaV1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&status.Conditions, metaV1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// finalizeStatus Records the Generation Reconciled and When the Credentials will Next be Refreshed, if Ever,
+// and Derives the Ready and Degraded Conditions from the Others
+func finalizeStatus(status *containerregistryv1beta1.AuthStatus, generation int64, requeueAfter time.Duration) {
+	status.ObservedGeneration = generation
+	status.NextRefreshTime = nil
+	if requeueAfter > 0 {
+		status.NextRefreshTime = &metaV1.Time{Time: time.Now().Add(requeueAfter)}
+	}
+
+	ready := metaV1.Condition{Status: metaV1.ConditionTrue, Reason: "Ready", Message: "Registry Credentials are Issued and the Secret is Up to Date"}
+	for _, conditionType := range []string{ConditionPolicyCompliant, ConditionTokenIssued, ConditionSecretSynced} {
+		condition := meta.FindStatusCondition(status.Conditions, conditionType)
+		if condition == nil {
+			if conditionType == ConditionPolicyCompliant {
+				continue
+			}
+			ready = metaV1.Condition{Status: metaV1.ConditionFalse, Reason: "Pending", Message: conditionType + " has not been Reported"}
+			break
+		}
+		if condition.Status != metaV1.ConditionTrue {
+			ready = metaV1.Condition{Status: metaV1.ConditionFalse, Reason: condition.Reason, Message: condition.Message}
+			break
+		}
+	}
+	setCondition(status, generation, ConditionReady, ready.Status, ready.Reason, ready.Message)
+
+	if status.Error == "" {
+		setCondition(status, generation, ConditionDegraded, metaV1.ConditionFalse, "AsExpected", "")
+	} else if ready.Status == metaV1.ConditionTrue {
+		setCondition(status, generation, ConditionDegraded, metaV1.ConditionTrue, "PartialFailure", status.Error)
+	} else {
+		setCondition(status, generation, ConditionDegraded, metaV1.ConditionTrue, ready.Reason, status.Error)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// Reasons of the Events Recorded on Auths and ClusterAuths
+const (
+	EventTokenIssued           = "TokenIssued"
+	EventSecretCreated         = "SecretCreated"
+	EventSecretUpdated         = "SecretUpdated"
+	EventSecretWriteFailed     = "SecretWriteFailed"
+	EventFederationFailed      = "FederationFailed"
+	EventServiceAccountMissing = "ServiceAccountMissing"
+	EventConfigMapInvalid      = "ConfigMapInvalid"
+	EventInvalidConfiguration  = "InvalidConfiguration"
+)
+
+// exchangeFailureEvent Returns the Reason and Message of the Event for a Registry whose Exchange Failed,
+// error is the Failed Step Returned by exchangeRegistry
+func exchangeFailureEvent(registry string, error string, err error) (string, string) {
+	var configMapError *provider.ConfigMapError
+	switch {
+	case errors.As(err, &configMapError):
+		return EventConfigMapInvalid, fmt.Sprintf("ConfigMap '%s' of Registry '%s' is Invalid: %v", configMapError.Name, registry, err)
+	case error == "Unable to Generate Kubernetes Token" && apierrors.IsNotFound(err):
+		return EventServiceAccountMissing, fmt.Sprintf("%s for Registry '%s': %v", error, registry, err)
+	case error == "Unsupported Container Registry" || error == "Invalid Container Registry Configuration":
+		return EventInvalidConfiguration, fmt.Sprintf("%s for Registry '%s': %v", error, registry, err)
+	}
+	if statusCode := provider.HTTPStatusCode(err); statusCode != 0 {
+		return EventFederationFailed, fmt.Sprintf("%s for Registry '%s', HTTP Status %d: %v", error, registry, statusCode, err)
+	}
+	return EventFederationFailed, fmt.Sprintf("%s for Registry '%s': %v", error, registry, err)
+}
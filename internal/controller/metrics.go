@@ -0,0 +1,125 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	tokenExchangeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "auth_token_exchange_duration_seconds",
+		Help:    "Time taken by a provider to exchange a Kubernetes token for registry credentials.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"provider", "result"})
+
+	kubernetesTokenRequests = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "auth_kubernetes_token_request_total",
+		Help: "Kubernetes Service Account tokens requested for federation.",
+	})
+
+	secretWrites = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_secret_write_total",
+		Help: "Image pull secret writes, by result: created, updated, unchanged or error.",
+	}, []string{"result"})
+
+	tokenExpiry = newTokenExpiryCollector()
+)
+
+func init() {
+	metrics.Registry.MustRegister(tokenExchangeDuration, kubernetesTokenRequests, secretWrites, tokenExpiry)
+}
+
+// metricResult Returns the result Label for an Operation's Error
+func metricResult(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+type tokenKey struct {
+	namespace string
+	name      string
+	registry  string
+}
+
+// tokenExpiryCollector Exports When Each Registry's Credentials Expire, and the Seconds Remaining,
+// Computed at Scrape Time so Alerts Fire Before Pull Secrets Lapse Even if Reconciles Stall
+type tokenExpiryCollector struct {
+	mutex       sync.Mutex
+	expirations map[tokenKey]time.Time
+
+	expiryTimestamp *prometheus.Desc
+	untilExpiry     *prometheus.Desc
+}
+
+func newTokenExpiryCollector() *tokenExpiryCollector {
+	labels := []string{"namespace", "name", "registry"}
+	return &tokenExpiryCollector{
+		expirations: map[tokenKey]time.Time{},
+		expiryTimestamp: prometheus.NewDesc("auth_token_expiry_timestamp_seconds",
+			"Unix time the registry credentials in the image pull secret expire.", labels, nil),
+		untilExpiry: prometheus.NewDesc("auth_token_seconds_until_expiry",
+			"Seconds until the registry credentials in the image pull secret expire.", labels, nil),
+	}
+}
+
+// set Replaces the Expirations Recorded for the Object with registries, Keyed by Registry Name
+func (c *tokenExpiryCollector) set(namespace string, name string, registries map[string]time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.deleteLocked(namespace, name)
+	for registry, expiration := range registries {
+		if !expiration.IsZero() {
+			c.expirations[tokenKey{namespace: namespace, name: name, registry: registry}] = expiration
+		}
+	}
+}
+
+// delete Forgets the Expirations Recorded for the Object
+func (c *tokenExpiryCollector) delete(namespace string, name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.deleteLocked(namespace, name)
+}
+
+func (c *tokenExpiryCollector) deleteLocked(namespace string, name string) {
+	for key := range c.expirations {
+		if key.namespace == namespace && key.name == name {
+			delete(c.expirations, key)
+		}
+	}
+}
+
+func (c *tokenExpiryCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- c.expiryTimestamp
+	descs <- c.untilExpiry
+}
+
+func (c *tokenExpiryCollector) Collect(metrics chan<- prometheus.Metric) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key, expiration := range c.expirations {
+		metrics <- prometheus.MustNewConstMetric(c.expiryTimestamp, prometheus.GaugeValue, float64(expiration.Unix()), key.namespace, key.name, key.registry)
+		metrics <- prometheus.MustNewConstMetric(c.untilExpiry, prometheus.GaugeValue, time.Until(expiration).Seconds(), key.namespace, key.name, key.registry)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/google"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/quay"
+)
+
+// matchPattern Reports Whether the Value Matches the Glob Pattern, where * Matches Any Characters
+func matchPattern(pattern string, value string) bool {
+	expression := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	matched, _ := regexp.MatchString(expression, value)
+	return matched
+}
+
+// patternAllowed Reports Whether the Value Matches no Deny Pattern, and an Allow Pattern when there are Any
+func patternAllowed(rule containerregistryv1beta1.PatternRule, value string) bool {
+	for _, pattern := range rule.Deny {
+		if matchPattern(pattern, value) {
+			return false
+		}
+	}
+	if len(rule.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range rule.Allow {
+		if matchPattern(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyViolations Returns the Fields of the Auth the AuthPolicy does not Allow
+func policyViolations(policy *containerregistryv1beta1.AuthPolicy, containerRegistryAuth *containerregistryv1beta1.Auth) []string {
+	var violations []string
+	check := func(rule containerregistryv1beta1.PatternRule, field string, value string) {
+		if patternAllowed(rule, value) {
+			return
+		}
+		violation := fmt.Sprintf("AuthPolicy '%s' does not allow %s '%s'", policy.Name, field, value)
+		if !slices.Contains(violations, violation) {
+			violations = append(violations, violation)
+		}
+	}
+
+	check(policy.Spec.ServiceAccounts, "serviceAccount", containerRegistryAuth.Spec.ServiceAccount)
+	for _, entry := range provider.Entries(containerRegistryAuth) {
+		spec := entry.Auth.Spec
+		check(policy.Spec.ContainerRegistries, "containerRegistry", spec.ContainerRegistry)
+		for _, audience := range spec.Audiences {
+			check(policy.Spec.Audiences, "audience", audience)
+		}
+		switch spec.ContainerRegistry {
+		case quay.Name:
+			check(policy.Spec.RobotAccounts, "quay.robotAccount", spec.Quay.RobotAccount)
+			check(policy.Spec.QuayURLs, "quay.url", quay.NormalizeURL(spec.Quay.URL))
+		case google.Name:
+			if spec.GoogleArtifactRegistry.Type == "inline" {
+				check(policy.Spec.GoogleServiceAccounts, "googleArtifactRegistry.googleServiceAccount", spec.GoogleArtifactRegistry.GoogleServiceAccount)
+			}
+		case google.SecretManagerName:
+			if spec.GoogleSecretManager.Type == "inline" {
+				check(policy.Spec.GoogleServiceAccounts, "googleSecretManager.googleServiceAccount", spec.GoogleSecretManager.GoogleServiceAccount)
+			}
+		}
+	}
+
+	return violations
+}
+
+// evaluatePolicies Applies the AuthPolicies Selecting the Auth's Namespace.
+// Returns the Violations, and the Shortest Maximum Token Lifetime, Zero when Unlimited.
+func evaluatePolicies(reconcilerContext context.Context, c client.Client, containerRegistryAuth *containerregistryv1beta1.Auth) ([]string, time.Duration, error) {
+	var policies containerregistryv1beta1.AuthPolicyList
+	if err := c.List(reconcilerContext, &policies); err != nil {
+		return nil, 0, err
+	}
+	if len(policies.Items) == 0 {
+		return nil, 0, nil
+	}
+
+	var namespace coreV1.Namespace
+	if err := c.Get(reconcilerContext, types.NamespacedName{Name: containerRegistryAuth.Namespace}, &namespace); err != nil {
+		return nil, 0, err
+	}
+
+	var violations []string
+	var maxTokenLifetime time.Duration
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		namespaceSelector, err := metaV1.LabelSelectorAsSelector(&policy.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, 0, fmt.Errorf("AuthPolicy '%s' has an invalid namespaceSelector: %w", policy.Name, err)
+		}
+		if !namespaceSelector.Matches(labels.Set(namespace.Labels)) {
+			continue
+		}
+
+		violations = append(violations, policyViolations(policy, containerRegistryAuth)...)
+
+		if lifetime := policy.Spec.MaxTokenLifetime; lifetime != nil && (maxTokenLifetime == 0 || lifetime.Duration < maxTokenLifetime) {
+			maxTokenLifetime = lifetime.Duration
+		}
+
+		if policy.Spec.MaxAuthsPerNamespace != nil {
+			position, err := authPosition(reconcilerContext, c, containerRegistryAuth)
+			if err != nil {
+				return nil, 0, err
+			}
+			if position >= int(*policy.Spec.MaxAuthsPerNamespace) {
+				violations = append(violations, fmt.Sprintf("AuthPolicy '%s' allows at most %d Auths in namespace '%s'", policy.Name, *policy.Spec.MaxAuthsPerNamespace, namespace.Name))
+			}
+		}
+	}
+
+	return violations, maxTokenLifetime, nil
+}
+
+// authPosition Returns the Index of the Auth Among the Auths in its Namespace, Oldest First
+func authPosition(reconcilerContext context.Context, c client.Client, containerRegistryAuth *containerregistryv1beta1.Auth) (int, error) {
+	var auths containerregistryv1beta1.AuthList
+	if err := c.List(reconcilerContext, &auths, client.InNamespace(containerRegistryAuth.Namespace)); err != nil {
+		return 0, err
+	}
+
+	position := 0
+	for _, auth := range auths.Items {
+		if !auth.DeletionTimestamp.IsZero() || auth.UID == containerRegistryAuth.UID {
+			continue
+		}
+		if auth.CreationTimestamp.Before(&containerRegistryAuth.CreationTimestamp) ||
+			(auth.CreationTimestamp.Equal(&containerRegistryAuth.CreationTimestamp) && auth.Name < containerRegistryAuth.Name) {
+			position++
+		}
+	}
+	return position, nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+)
+
+var _ = Describe("AuthPolicy Patterns", func() {
+	DescribeTable("Matching a Value Against a Glob Pattern",
+		func(pattern string, value string, matches bool) {
+			Expect(matchPattern(pattern, value)).To(Equal(matches))
+		},
+		Entry("Exact Values", "quay.io", "quay.io", true),
+		Entry("Longer Values", "quay.io", "quay.iox", false),
+		Entry("Values with a Prefix", "quay.io", "xquay.io", false),
+		Entry("Empty Patterns Match Empty Values", "", "", true),
+		Entry("Empty Patterns Match Nothing Else", "", "quay.io", false),
+		Entry("Wildcards Match Empty Values", "*", "", true),
+		Entry("Wildcards Match Slashes and Colons", "*", "anything/at:all", true),
+		Entry("Subdomains", "*.gcr.io", "us.gcr.io", true),
+		Entry("Wildcards Before a Dot Need a Subdomain", "*.gcr.io", "gcr.io", false),
+		Entry("Patterns are Anchored", "*.gcr.io", "us.gcr.io.evil.com", false),
+		Entry("Trailing Wildcards", "quay.io/*", "quay.io/org/app", true),
+		Entry("Wildcards in the Middle", "quay.io/*/app", "quay.io/org/app", true),
+		Entry("Wildcards in the Middle Keep the Rest", "quay.io/*/app", "quay.io/org/other", false),
+		Entry("Repeated Wildcards", "**", "quay.io", true),
+		Entry("Dots are Literal", "quay.io", "quayxio", false),
+		Entry("Parentheses are Literal", "registry-(dev)", "registry-(dev)", true),
+		Entry("Parentheses do not Group", "registry-(dev)", "registry-dev", false),
+		Entry("Plus is Literal", "a+b", "aab", false),
+		Entry("Brackets are Literal", "[abc]", "a", false),
+		Entry("Anchors are Literal", "^quay.io$", "^quay.io$", true),
+		Entry("Backslashes are Literal", `quay\.io`, `quay\.io`, true),
+	)
+
+	DescribeTable("Allowing a Value by a Rule",
+		func(rule containerregistryv1beta1.PatternRule, value string, allowed bool) {
+			Expect(patternAllowed(rule, value)).To(Equal(allowed))
+		},
+		Entry("Empty Rules Allow Everything", containerregistryv1beta1.PatternRule{}, "quay.io", true),
+		Entry("Allowed", containerregistryv1beta1.PatternRule{Allow: []string{"quay.io", "*.gcr.io"}}, "us.gcr.io", true),
+		Entry("Not Allowed", containerregistryv1beta1.PatternRule{Allow: []string{"quay.io"}}, "docker.io", false),
+		Entry("Denied", containerregistryv1beta1.PatternRule{Deny: []string{"docker.io"}}, "docker.io", false),
+		Entry("Not Denied", containerregistryv1beta1.PatternRule{Deny: []string{"docker.io"}}, "quay.io", true),
+		Entry("Deny Overrides Allow", containerregistryv1beta1.PatternRule{Allow: []string{"*"}, Deny: []string{"docker.io"}}, "docker.io", false),
+		Entry("Allowed and not Denied", containerregistryv1beta1.PatternRule{Allow: []string{"*.gcr.io"}, Deny: []string{"eu.gcr.io"}}, "us.gcr.io", true),
+		Entry("Empty Values are Only Allowed by Matching Patterns", containerregistryv1beta1.PatternRule{Allow: []string{"quay.io"}}, "", false),
+	)
+})
@@ -0,0 +1,50 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/artifactory"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/aws"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/azure"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/google"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/harbor"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/mock"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/quay"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/tokenexchange"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/vault"
+)
+
+// DefaultProviders returns a Registry with every built in Provider registered.
+// The mock provider is only registered when given a signer.
+func DefaultProviders(mockSigner *mock.Signer) *provider.Registry {
+	providers := provider.NewRegistry()
+	providers.Register(quay.Name, quay.Provider{})
+	providers.Register(google.Name, google.Provider{})
+	providers.Register(google.SecretManagerName, google.SecretManagerProvider{})
+	providers.Register(aws.Name, aws.Provider{})
+	providers.Register(aws.SecretsManagerName, aws.SecretsManagerProvider{})
+	providers.Register(azure.Name, azure.Provider{})
+	providers.Register(artifactory.Name, artifactory.Provider{})
+	providers.Register(tokenexchange.Name, tokenexchange.Provider{})
+	providers.Register(vault.Name, vault.Provider{})
+	providers.Register(harbor.Name, harbor.Provider{})
+	if mockSigner != nil {
+		providers.Register(mock.Name, mock.Provider{Signer: mockSigner})
+	}
+	return providers
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/jwt"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/kubernetes"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/tracing"
+)
+
+// exchangeRegistry Exchanges a Kubernetes Token for the Entry's Registry Credentials.
+// Kubernetes Tokens are Minted Once per Audience Set and Cached in kubernetesTokens, and Never for Tokenless Providers.
+// On Failure the Returned String Describes the Failed Step.
+func exchangeRegistry(reconcilerContext context.Context, c client.Client, providers *provider.Registry, containerRegistryAuth *containerregistryv1beta1.Auth, entry provider.Entry, kubernetesTokens map[string]string) (*provider.Credentials, string, error) {
+	registryProvider, err := providers.Get(entry.Auth.Spec.ContainerRegistry)
+	if err != nil {
+		return nil, "Unsupported Container Registry", permanent(err)
+	}
+
+	err = registryProvider.Validate(entry.Auth)
+	if err != nil {
+		return nil, "Invalid Container Registry Configuration", permanent(err)
+	}
+
+	audiences := strings.Join(entry.Auth.Spec.Audiences, ",")
+	kubernetesToken, ok := kubernetesTokens[audiences]
+	if _, tokenless := registryProvider.(provider.Tokenless); !ok && !tokenless {
+		kubernetesAuth := kubernetes.New(c)
+		kubernetesTokenRequests.Inc()
+		token, err := kubernetesAuth.GetKubernetesAuthToken(reconcilerContext, containerRegistryAuth.Spec.ServiceAccount, containerRegistryAuth.Namespace, tokenExpirationSeconds, entry.Auth.Spec.Audiences)
+		if err != nil {
+			return nil, "Unable to Generate Kubernetes Token", err
+		}
+
+		kubernetesTokenIssuer, err := jwt.Issuer(token.Status.Token)
+		if err != nil {
+			return nil, "Unable to Generate Kubernetes Token Issuer", err
+		}
+		containerRegistryAuth.Status.FederationConfiguration.Issuer = kubernetesTokenIssuer
+
+		kubernetesTokenSubject, err := jwt.Subject(token.Status.Token)
+		if err != nil {
+			return nil, "Unable to Generate Kubernetes Token Subject", err
+		}
+		containerRegistryAuth.Status.FederationConfiguration.Subject = kubernetesTokenSubject
+
+		kubernetesToken = token.Status.Token
+		kubernetesTokens[audiences] = kubernetesToken
+	}
+
+	exchangeContext, span := tracer.Start(reconcilerContext, "Exchange", trace.WithAttributes(
+		attribute.String("registry", entry.Name),
+		attribute.String("provider", entry.Auth.Spec.ContainerRegistry),
+	))
+	exchangeStart := time.Now()
+	credentials, err := registryProvider.Exchange(exchangeContext, provider.Request{
+		Client:       c,
+		Auth:         entry.Auth,
+		SubjectToken: kubernetesToken,
+	})
+	tokenExchangeDuration.WithLabelValues(entry.Auth.Spec.ContainerRegistry, metricResult(err)).Observe(time.Since(exchangeStart).Seconds())
+	tracing.End(span, err)
+	if err != nil {
+		return nil, "Unable to Exchange Kubernetes Token for Registry Credentials", err
+	}
+
+	return credentials, "", nil
+}
+
+// secretUpToDate Reports Whether the Existing Secret Already Holds the Data, Annotations, Labels and Owners to be Written
+func secretUpToDate(existing *coreV1.Secret, imagePullSecret *coreV1.Secret) bool {
+	if existing.Type != imagePullSecret.Type || len(existing.Data) != len(imagePullSecret.StringData) ||
+		!equality.Semantic.DeepEqual(existing.OwnerReferences, imagePullSecret.OwnerReferences) {
+		return false
+	}
+	for key, value := range imagePullSecret.StringData {
+		if string(existing.Data[key]) != value {
+			return false
+		}
+	}
+	for key, value := range imagePullSecret.Annotations {
+		if existing.Annotations[key] != value {
+			return false
+		}
+	}
+	for key, value := range imagePullSecret.Labels {
+		if existing.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// writeImagePullSecret Updates the Image Pull Secret, Creating it when it does not Exist, and Leaving it when it is Up to Date.
+// Reports Whether the Secret was Created.
+func writeImagePullSecret(reconcilerContext context.Context, c client.Client, imagePullSecret *coreV1.Secret) (created bool, err error) {
+	reconcilerContext, span := tracer.Start(reconcilerContext, "WriteImagePullSecret", trace.WithAttributes(
+		attribute.String("k8s.namespace.name", imagePullSecret.Namespace),
+		attribute.String("k8s.secret.name", imagePullSecret.Name),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	existing := &coreV1.Secret{}
+	if c.Get(reconcilerContext, client.ObjectKeyFromObject(imagePullSecret), existing) == nil && secretUpToDate(existing, imagePullSecret) {
+		secretWrites.WithLabelValues("unchanged").Inc()
+		return false, nil
+	}
+	if err = c.Update(reconcilerContext, imagePullSecret); err == nil {
+		secretWrites.WithLabelValues("updated").Inc()
+		return false, nil
+	}
+	if err = c.Create(reconcilerContext, imagePullSecret); err != nil {
+		secretWrites.WithLabelValues("error").Inc()
+		return false, err
+	}
+	secretWrites.WithLabelValues("created").Inc()
+	return true, nil
+}
+
+// resetStatus Clears the Status Fields Describing the Last Exchange
+func resetStatus(containerRegistryAuth *containerregistryv1beta1.Auth) {
+	containerRegistryAuth.Status.Error = ""
+	containerRegistryAuth.Status.TokenExpiration = ""
+	containerRegistryAuth.Status.ExpirationTime = nil
+	containerRegistryAuth.Status.FederationConfiguration.Issuer = ""
+	containerRegistryAuth.Status.FederationConfiguration.Subject = ""
+	containerRegistryAuth.Status.Registries = nil
+	containerRegistryAuth.Status.Hosts = nil
+}
+
+// exchangeRegistries Exchanges Credentials for Every Registry of the Auth and Merges them for One .dockerconfigjson.
+// The Auth's Status and TokenIssued Condition are Updated, and an Error is Returned when Any Registry Failed.
+// The Credentials in previous are Kept for Failed Registries Until they Expire, and
+// the Merged Credentials are Only Nil when None could be Exchanged or Kept.
+// Credentials Valid for Longer than maxTokenLifetime are Rejected, when it is Set.
+// Issued Credentials and Failures are Recorded as Events and Metrics on object.
+// The Returned Expiration is that of the Earliest Expiring Registry.
+func exchangeRegistries(reconcilerContext context.Context, c client.Client, providers *provider.Registry, recorder record.EventRecorder, object client.Object, containerRegistryAuth *containerregistryv1beta1.Auth, maxTokenLifetime time.Duration, previous kubernetes.ImagePullSecretAuths) (kubernetes.ImagePullSecretAuths, time.Time, error) {
+	log := log.FromContext(reconcilerContext)
+
+	//Reset Error
+	previousRegistries := containerRegistryAuth.Status.Registries
+	resetStatus(containerRegistryAuth)
+
+	multipleRegistries := len(containerRegistryAuth.Spec.Registries) > 0
+	kubernetesTokens := map[string]string{}
+	imagePullSecretAuths := kubernetes.ImagePullSecretAuths{}
+	var registryErrors []string
+	var errs []error
+	var expiration time.Time
+	expirations := map[string]time.Time{}
+
+	for _, entry := range provider.Entries(containerRegistryAuth) {
+		credentials, error, err := exchangeRegistry(reconcilerContext, c, providers, containerRegistryAuth, entry, kubernetesTokens)
+		if err == nil && maxTokenLifetime > 0 && credentials.Expiration.After(time.Now().Add(maxTokenLifetime)) {
+			error = "Registry Credentials Exceed the Maximum Token Lifetime"
+			err = permanent(fmt.Errorf("credentials valid until %s exceed the AuthPolicy maximum token lifetime of %s", credentials.Expiration.UTC().String(), maxTokenLifetime))
+		}
+		if err != nil {
+			log.Error(err, error, "registry", entry.Name)
+			reason, message := exchangeFailureEvent(entry.Name, error, err)
+			recorder.Event(object, coreV1.EventTypeWarning, reason, message)
+			if !multipleRegistries {
+				containerRegistryAuth.Status.Error = err.Error()
+				setCondition(&containerRegistryAuth.Status, containerRegistryAuth.Generation, ConditionTokenIssued, metaV1.ConditionFalse, "ExchangeFailed", err.Error())
+				return nil, expiration, err
+			}
+			registryStatus := containerregistryv1beta1.RegistryStatus{Name: entry.Name}
+			if kept, ok := keepPreviousCredentials(imagePullSecretAuths, previous, previousRegistries, entry.Name); ok {
+				log.Info("Keeping the Previous Credentials Until they Expire", "registry", entry.Name, "expiration", kept.ExpirationTime.Time)
+				registryStatus = kept
+				expirations[entry.Name] = kept.ExpirationTime.Time
+				if expiration.IsZero() || kept.ExpirationTime.Time.Before(expiration) {
+					expiration = kept.ExpirationTime.Time
+				}
+			}
+			registryStatus.Error = err.Error()
+			containerRegistryAuth.Status.Registries = append(containerRegistryAuth.Status.Registries, registryStatus)
+			registryErrors = append(registryErrors, fmt.Sprintf("%s: %v", entry.Name, err))
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name, err))
+			continue
+		}
+
+		imagePullSecretAuths.Add(credentials.Username, credentials.Password, credentials.Registries)
+		expirations[entry.Name] = credentials.Expiration
+		if expiration.IsZero() || (!credentials.Expiration.IsZero() && credentials.Expiration.Before(expiration)) {
+			expiration = credentials.Expiration
+		}
+		if multipleRegistries {
+			registryStatus := containerregistryv1beta1.RegistryStatus{
+				Name:            entry.Name,
+				TokenExpiration: credentials.Expiration.UTC().String(),
+				Hosts:           credentials.Registries,
+			}
+			if !credentials.Expiration.IsZero() {
+				registryStatus.ExpirationTime = &metaV1.Time{Time: credentials.Expiration}
+			}
+			containerRegistryAuth.Status.Registries = append(containerRegistryAuth.Status.Registries, registryStatus)
+		}
+	}
+
+	containerRegistryAuth.Status.Error = strings.Join(registryErrors, "; ")
+	if len(imagePullSecretAuths) == 0 {
+		setCondition(&containerRegistryAuth.Status, containerRegistryAuth.Generation, ConditionTokenIssued, metaV1.ConditionFalse, "ExchangeFailed", containerRegistryAuth.Status.Error)
+		return nil, expiration, errors.Join(errs...)
+	}
+	containerRegistryAuth.Status.TokenExpiration = expiration.UTC().String()
+	if !expiration.IsZero() {
+		containerRegistryAuth.Status.ExpirationTime = &metaV1.Time{Time: expiration}
+	}
+	containerRegistryAuth.Status.LastRefreshTime = &metaV1.Time{Time: time.Now()}
+	tokenExpiry.set(object.GetNamespace(), object.GetName(), expirations)
+	containerRegistryAuth.Status.Hosts = imagePullSecretAuths.Registries()
+	setCondition(&containerRegistryAuth.Status, containerRegistryAuth.Generation, ConditionTokenIssued, metaV1.ConditionTrue, "TokensIssued", fmt.Sprintf("Credentials Issued for %s", strings.Join(containerRegistryAuth.Status.Hosts, ", ")))
+	recorder.Eventf(object, coreV1.EventTypeNormal, EventTokenIssued, "Issued Credentials for %s, Expiring at %s", strings.Join(containerRegistryAuth.Status.Hosts, ", "), containerRegistryAuth.Status.TokenExpiration)
+
+	return imagePullSecretAuths, expiration, errors.Join(errs...)
+}
+
+// keepPreviousCredentials Adds the Credentials Previously Written for the Registry to imagePullSecretAuths, when they have not Expired.
+// Returns the Registry's Previous Status.
+func keepPreviousCredentials(imagePullSecretAuths kubernetes.ImagePullSecretAuths, previous kubernetes.ImagePullSecretAuths, previousRegistries []containerregistryv1beta1.RegistryStatus, name string) (containerregistryv1beta1.RegistryStatus, bool) {
+	index := slices.IndexFunc(previousRegistries, func(registryStatus containerregistryv1beta1.RegistryStatus) bool {
+		return registryStatus.Name == name
+	})
+	if index < 0 {
+		return containerregistryv1beta1.RegistryStatus{}, false
+	}
+	registryStatus := previousRegistries[index]
+	if registryStatus.ExpirationTime == nil || !registryStatus.ExpirationTime.After(time.Now()) || len(registryStatus.Hosts) == 0 {
+		return containerregistryv1beta1.RegistryStatus{}, false
+	}
+	for _, host := range registryStatus.Hosts {
+		if _, ok := previous[host]; !ok {
+			return containerregistryv1beta1.RegistryStatus{}, false
+		}
+	}
+
+	for _, host := range registryStatus.Hosts {
+		imagePullSecretAuths[host] = previous[host]
+	}
+	registryStatus.Error = ""
+	return registryStatus, true
+}
+
+// previousCredentials Returns the Credentials in the Image Pull Secret, when it is Controlled by the Owner
+func previousCredentials(imagePullSecret *coreV1.Secret, owner metaV1.Object) kubernetes.ImagePullSecretAuths {
+	if imagePullSecret == nil || !metaV1.IsControlledBy(imagePullSecret, owner) {
+		return nil
+	}
+	auths, err := kubernetes.ParseImagePullSecretAuths(imagePullSecret.Data[".dockerconfigjson"])
+	if err != nil {
+		return nil
+	}
+	return auths
+}
+
+// pullSecretMetadata Describes the Credentials Last Issued for the Auth, to Annotate the Image Pull Secret With
+func pullSecretMetadata(containerRegistryAuth *containerregistryv1beta1.Auth, expiration time.Time, hash string) kubernetes.PullSecretMetadata {
+	var providers []string
+	for _, entry := range provider.Entries(containerRegistryAuth) {
+		if !slices.Contains(providers, entry.Auth.Spec.ContainerRegistry) {
+			providers = append(providers, entry.Auth.Spec.ContainerRegistry)
+		}
+	}
+
+	return kubernetes.PullSecretMetadata{
+		IssuedAt:   containerRegistryAuth.Status.LastRefreshTime.Time,
+		Expiration: expiration,
+		Provider:   strings.Join(providers, ","),
+		SpecHash:   hash,
+	}
+}
+
+// reuseCredentials Updates the Auth's Status and Metrics for the Fresh Credentials Already in its Image Pull Secret,
+// Every Registry is Reported with the Secret's Expiration, the Earliest of Them
+func reuseCredentials(object client.Object, containerRegistryAuth *containerregistryv1beta1.Auth, metadata kubernetes.PullSecretMetadata) {
+	containerRegistryAuth.Status.TokenExpiration = metadata.Expiration.UTC().String()
+	containerRegistryAuth.Status.ExpirationTime = nil
+	if !metadata.Expiration.IsZero() {
+		containerRegistryAuth.Status.ExpirationTime = &metaV1.Time{Time: metadata.Expiration}
+	}
+	containerRegistryAuth.Status.LastRefreshTime = &metaV1.Time{Time: metadata.IssuedAt}
+
+	expirations := map[string]time.Time{}
+	for _, entry := range provider.Entries(containerRegistryAuth) {
+		expirations[entry.Name] = metadata.Expiration
+	}
+	tokenExpiry.set(object.GetNamespace(), object.GetName(), expirations)
+
+	setCondition(&containerRegistryAuth.Status, containerRegistryAuth.Generation, ConditionTokenIssued, metaV1.ConditionTrue, "CredentialsReused",
+		fmt.Sprintf("Credentials Issued at %s are Reused Until they are Due for Rotation", metadata.IssuedAt.UTC().String()))
+}
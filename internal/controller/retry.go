@@ -0,0 +1,177 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+const (
+	// Delay Before the First Retry of a Transient Failure, Doubled on Each Attempt
+	retryBaseDelay = 5 * time.Second
+	// Longest Delay Between Retries of a Transient Failure
+	retryMaxDelay = 10 * time.Minute
+	// Fraction of the Delay Added at Random, so Auths Failing Together do not Retry Together
+	retryJitter = 0.1
+)
+
+// permanentError Marks a Failure that Retrying will not Fix
+type permanentError struct {
+	error
+}
+
+func (e permanentError) Unwrap() error {
+	return e.error
+}
+
+func permanent(err error) error {
+	return permanentError{err}
+}
+
+// classifyError Reports Whether the Failure is Transient, and How Long the Registry Asked to Wait Before Retrying.
+// Network Errors, 5xx, 408 and 429 are Transient. Invalid Specs, Missing Dependencies and Other 4xx are Permanent.
+func classifyError(err error) (bool, time.Duration) {
+	// Any Transient Failure Among Several Registries Makes it Worth Retrying, Unless they were Marked Permanent Together
+	for wrapped := err; wrapped != nil; wrapped = errors.Unwrap(wrapped) {
+		if _, ok := wrapped.(permanentError); ok {
+			break
+		}
+		if joined, ok := wrapped.(interface{ Unwrap() []error }); ok {
+			transient, retryAfter := false, time.Duration(0)
+			for _, err := range joined.Unwrap() {
+				errTransient, errRetryAfter := classifyError(err)
+				transient = transient || errTransient
+				retryAfter = max(retryAfter, errRetryAfter)
+			}
+			return transient, retryAfter
+		}
+	}
+
+	var permanentErr permanentError
+	var httpError *provider.HTTPError
+	var configMapError *provider.ConfigMapError
+	switch {
+	case errors.As(err, &permanentErr), errors.As(err, &configMapError):
+		return false, 0
+	case errors.As(err, &httpError):
+		switch {
+		case httpError.StatusCode >= 500, httpError.StatusCode == http.StatusTooManyRequests, httpError.StatusCode == http.StatusRequestTimeout:
+			return true, httpError.RetryAfter
+		case httpError.StatusCode >= 400:
+			return false, 0
+		}
+	case apierrors.IsNotFound(err), apierrors.IsForbidden(err), apierrors.IsUnauthorized(err), apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return false, 0
+	}
+	return true, 0
+}
+
+// pendingRetry Returns How Long Until the Retry Scheduled for the Current Generation is Due, ok is False when None is Pending
+func pendingRetry(status *containerregistryv1beta1.AuthStatus, generation int64) (time.Duration, bool) {
+	if status.Retry == nil || status.Retry.NextRetryTime == nil || status.ObservedGeneration != generation {
+		return 0, false
+	}
+	untilRetry := time.Until(status.Retry.NextRetryTime.Time)
+	return untilRetry, untilRetry > 0
+}
+
+// resetRetry Starts the Retries Over when the Spec has Changed Since the Last Reconcile
+func resetRetry(status *containerregistryv1beta1.AuthStatus, generation int64) {
+	if status.ObservedGeneration != generation {
+		status.Retry = nil
+	}
+}
+
+// retryRateLimiter is the Controllers' Workqueue Rate Limiter. A Transient Failure Waits the Delay its Reconcile
+// Recorded in Status.Retry, so the Backoff Honours Retry-After and Survives Restarts. Other Errors Back Off Exponentially.
+type retryRateLimiter struct {
+	workqueue.TypedRateLimiter[reconcile.Request]
+	mutex    sync.Mutex
+	reserved map[reconcile.Request]time.Duration
+}
+
+func newRetryRateLimiter() *retryRateLimiter {
+	return &retryRateLimiter{
+		TypedRateLimiter: workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](retryBaseDelay, retryMaxDelay),
+		reserved:         map[reconcile.Request]time.Duration{},
+	}
+}
+
+func (r *retryRateLimiter) When(item reconcile.Request) time.Duration {
+	r.mutex.Lock()
+	delay, ok := r.reserved[item]
+	delete(r.reserved, item)
+	r.mutex.Unlock()
+	if ok {
+		return delay
+	}
+	return r.TypedRateLimiter.When(item)
+}
+
+func (r *retryRateLimiter) Forget(item reconcile.Request) {
+	r.mutex.Lock()
+	delete(r.reserved, item)
+	r.mutex.Unlock()
+	r.TypedRateLimiter.Forget(item)
+}
+
+// scheduleRetry Records the Failed Attempt in Status.Retry, and Returns the Error the Reconcile Returns so the
+// Workqueue Retries it. Nil is Returned when the Credentials that did not Fail are Due Sooner, in refresh, 0 when there are None,
+// and for Permanent Failures, which are not Retried Until the Spec or an Object it References Changes.
+func (r *retryRateLimiter) scheduleRetry(req reconcile.Request, status *containerregistryv1beta1.AuthStatus, err error, refresh time.Duration) error {
+	attempts := int32(1)
+	if status.Retry != nil {
+		attempts = status.Retry.Attempts + 1
+	}
+
+	transient, retryAfter := classifyError(err)
+	if !transient {
+		status.Retry = &containerregistryv1beta1.RetryStatus{
+			Attempts:       attempts,
+			Classification: containerregistryv1beta1.RetryPermanent,
+		}
+		return nil
+	}
+
+	delay := retryBaseDelay << min(attempts-1, 16)
+	delay = max(wait.Jitter(min(delay, retryMaxDelay), retryJitter), retryAfter)
+	status.Retry = &containerregistryv1beta1.RetryStatus{
+		Attempts:       attempts,
+		Classification: containerregistryv1beta1.RetryTransient,
+		NextRetryTime:  &metaV1.Time{Time: time.Now().Add(delay)},
+	}
+	if refresh > 0 && refresh < delay {
+		return nil
+	}
+
+	r.mutex.Lock()
+	r.reserved[req] = delay
+	r.mutex.Unlock()
+	return err
+}
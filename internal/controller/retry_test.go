@@ -0,0 +1,71 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+var _ = Describe("Retry", func() {
+	unavailable := &provider.HTTPError{StatusCode: 503, RetryAfter: 30 * time.Second}
+	tooManyRequests := &provider.HTTPError{StatusCode: 429, RetryAfter: 2 * time.Minute}
+	forbidden := &provider.HTTPError{StatusCode: 403}
+	invalid := permanent(errors.New("mock.tokenLifetime must be positive"))
+
+	DescribeTable("Classifying Failures",
+		func(err error, transient bool, retryAfter time.Duration) {
+			gotTransient, gotRetryAfter := classifyError(err)
+			Expect(gotTransient).To(Equal(transient))
+			Expect(gotRetryAfter).To(Equal(retryAfter))
+		},
+		Entry("Unknown Errors are Transient", errors.New("connection reset"), true, time.Duration(0)),
+		Entry("Network Errors", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true, time.Duration(0)),
+		Entry("Timeouts", context.DeadlineExceeded, true, time.Duration(0)),
+		Entry("Server Errors Honour Retry-After", unavailable, true, 30*time.Second),
+		Entry("Too Many Requests", tooManyRequests, true, 2*time.Minute),
+		Entry("Request Timeout", &provider.HTTPError{StatusCode: 408}, true, time.Duration(0)),
+		Entry("Client Errors", forbidden, false, time.Duration(0)),
+		Entry("Unauthorized", &provider.HTTPError{StatusCode: 401}, false, time.Duration(0)),
+		Entry("Permanent Errors", invalid, false, time.Duration(0)),
+		Entry("Wrapped Permanent Errors", fmt.Errorf("registry: %w", invalid), false, time.Duration(0)),
+		Entry("Permanent Wrapping a Transient Error", permanent(unavailable), false, time.Duration(0)),
+		Entry("Wrapped HTTP Errors", fmt.Errorf("Unable to Exchange Token: %w", unavailable), true, 30*time.Second),
+		Entry("Config Map Errors", &provider.ConfigMapError{Name: "gcp", Err: errors.New("not found")}, false, time.Duration(0)),
+		Entry("Missing Secrets", fmt.Errorf("secret 'harbor-admin' not found. Error: %w",
+			apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "harbor-admin")), false, time.Duration(0)),
+		Entry("Forbidden API Requests", apierrors.NewForbidden(schema.GroupResource{Resource: "serviceaccounts"}, "wif", errors.New("denied")), false, time.Duration(0)),
+		Entry("API Server Timeouts", apierrors.NewTimeoutError("slow", 5), true, time.Duration(0)),
+		Entry("Joined Permanent Errors", errors.Join(invalid, forbidden), false, time.Duration(0)),
+		Entry("Joined with Any Transient Error", errors.Join(invalid, unavailable), true, 30*time.Second),
+		Entry("Joined Errors Wait for the Longest Retry-After", errors.Join(unavailable, tooManyRequests), true, 2*time.Minute),
+		Entry("Wrapped Joined Errors", fmt.Errorf("registries: %w", errors.Join(invalid, unavailable)), true, 30*time.Second),
+		Entry("Nested Joined Errors", errors.Join(invalid, errors.Join(forbidden, unavailable)), true, 30*time.Second),
+		Entry("Permanent Joined Errors", permanent(errors.Join(invalid, unavailable)), false, time.Duration(0)),
+		Entry("Errors Wrapping Several Errors", fmt.Errorf("%w; %w", invalid, unavailable), true, 30*time.Second),
+	)
+})
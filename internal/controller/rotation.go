@@ -0,0 +1,165 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/kubernetes"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+const (
+	// Refresh this Long Before Expiry, Unless Spec.Rotation.RefreshBefore is Set
+	defaultRefreshBefore = 60 * time.Second
+	// Wait at Least this Long Between Refreshes, Unless Spec.Rotation.MinInterval is Set
+	defaultMinInterval = 60 * time.Second
+)
+
+// parsePercentage Parses a Percentage such as 20% into a Fraction, ok is False when it is not a Percentage
+func parsePercentage(value string) (float64, bool) {
+	number, found := strings.CutSuffix(value, "%")
+	if !found {
+		return 0, false
+	}
+	percentage, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, false
+	}
+	return percentage / 100, true
+}
+
+// refreshBefore Returns How Long Before Expiry to Refresh Credentials with the Lifetime.
+// The CRD Validates Spec.Rotation.RefreshBefore, so Unparsable Values Fall Back to the Default.
+func refreshBefore(rotation *containerregistryv1beta1.Rotation, lifetime time.Duration) time.Duration {
+	if rotation == nil || rotation.RefreshBefore == "" {
+		return defaultRefreshBefore
+	}
+	if fraction, ok := parsePercentage(rotation.RefreshBefore); ok {
+		return time.Duration(fraction * float64(lifetime))
+	}
+	if duration, err := time.ParseDuration(rotation.RefreshBefore); err == nil {
+		return duration
+	}
+	return defaultRefreshBefore
+}
+
+// refreshTime Returns When Credentials Issued at issued, that Expire at expiration, are Due for Rotation.
+// Credentials Without an Expiry are Refreshed as if they Lasted as Long as the Kubernetes Token.
+func refreshTime(rotation *containerregistryv1beta1.Rotation, issued time.Time, expiration time.Time) time.Time {
+	if expiration.IsZero() {
+		expiration = issued.Add(tokenExpirationSeconds * time.Second)
+	}
+	return expiration.Add(-refreshBefore(rotation, expiration.Sub(issued)))
+}
+
+// refreshAfter Returns How Long to Wait Before Refreshing Credentials Issued at issued, that Expire at expiration.
+// The Minimum Interval Never Delays the Refresh Past the Expiry.
+func refreshAfter(rotation *containerregistryv1beta1.Rotation, issued time.Time, expiration time.Time) time.Duration {
+	if expiration.IsZero() {
+		expiration = issued.Add(tokenExpirationSeconds * time.Second)
+	}
+	delay := time.Until(refreshTime(rotation, issued, expiration))
+
+	// Jitter Only Ever Refreshes Earlier, so the Credentials do not Lapse
+	if rotation != nil && delay > 0 {
+		if fraction, ok := parsePercentage(rotation.Jitter); ok {
+			delay -= time.Duration(rand.Float64() * fraction * float64(delay))
+		}
+	}
+
+	minInterval := defaultMinInterval
+	if rotation != nil && rotation.MinInterval != nil {
+		minInterval = rotation.MinInterval.Duration
+	}
+	// A Zero RequeueAfter would Never Refresh, so Overdue Credentials are Refreshed After a Second
+	return max(min(max(delay, minInterval), time.Until(expiration)), time.Second)
+}
+
+// specHash Returns a Hash of the Spec, the Config Maps it References and the Keys its Providers Sign With, Recorded on the
+// Image Pull Secret to Tell Whether it was Issued for the Current Configuration. Config Maps that Cannot be Read are Left Out.
+func specHash(reconcilerContext context.Context, c client.Client, providers *provider.Registry, containerRegistryAuth *containerregistryv1beta1.Auth) string {
+	configMaps := map[string]map[string]string{}
+	for _, name := range referencedConfigMaps(containerRegistryAuth) {
+		var configMap coreV1.ConfigMap
+		if err := c.Get(reconcilerContext, types.NamespacedName{Name: name, Namespace: containerRegistryAuth.Namespace}, &configMap); err == nil {
+			configMaps[name] = configMap.Data
+		}
+	}
+
+	// Credentials Signed by a Key that has Since Changed, such as the Mock's on Restart, are no Longer Valid
+	keys := map[string]string{}
+	for _, entry := range provider.Entries(containerRegistryAuth) {
+		if registryProvider, err := providers.Get(entry.Auth.Spec.ContainerRegistry); err == nil {
+			if keyIdentifier, ok := registryProvider.(provider.KeyIdentifier); ok {
+				keys[entry.Name] = keyIdentifier.KeyID()
+			}
+		}
+	}
+
+	specJSON, _ := json.Marshal(struct {
+		Spec       containerregistryv1beta1.AuthSpec `json:"spec"`
+		ConfigMaps map[string]map[string]string      `json:"configMaps,omitempty"`
+		Keys       map[string]string                 `json:"keys,omitempty"`
+	}{containerRegistryAuth.Spec, configMaps, keys})
+	hash := sha256.Sum256(specJSON)
+	return hex.EncodeToString(hash[:])
+}
+
+// credentialsReusable Reports Whether the Last Exchange Issued Credentials for Every Registry, or the Retry of those that Failed
+// is not Due Yet, so Fresh Credentials in the Image Pull Secret can be Reused Rather than Exchanged Again
+func credentialsReusable(status *containerregistryv1beta1.AuthStatus, generation int64) bool {
+	tokenIssued := meta.FindStatusCondition(status.Conditions, ConditionTokenIssued)
+	if tokenIssued != nil && tokenIssued.Status == metaV1.ConditionTrue &&
+		!slices.ContainsFunc(status.Registries, func(registryStatus containerregistryv1beta1.RegistryStatus) bool { return registryStatus.Error != "" }) {
+		return true
+	}
+	_, pending := pendingRetry(status, generation)
+	return pending
+}
+
+// freshCredentials Reports Whether the Image Pull Secret Written for owner Holds Credentials Issued for the Spec Hashed to hash,
+// that are not yet Due for Rotation, nor Valid for Longer than maxTokenLifetime, when it is Set.
+// Secrets Edited Since they were Written are not Fresh, so the Credentials are Minted and Written Again.
+func freshCredentials(imagePullSecret *coreV1.Secret, owner metaV1.Object, rotation *containerregistryv1beta1.Rotation, hash string, maxTokenLifetime time.Duration) (kubernetes.PullSecretMetadata, bool) {
+	metadata, err := kubernetes.ParsePullSecretMetadata(imagePullSecret)
+	if err != nil || metadata.SpecHash != hash || !metaV1.IsControlledBy(imagePullSecret, owner) {
+		return metadata, false
+	}
+	if imagePullSecret.Type != coreV1.SecretTypeDockerConfigJson || metadata.DataHash != kubernetes.DockerConfigHash(imagePullSecret.Data[".dockerconfigjson"]) {
+		return metadata, false
+	}
+	if maxTokenLifetime > 0 && !metadata.Expiration.IsZero() && metadata.Expiration.After(time.Now().Add(maxTokenLifetime)) {
+		return metadata, false
+	}
+	return metadata, time.Now().Before(refreshTime(rotation, metadata.IssuedAt, metadata.Expiration))
+}
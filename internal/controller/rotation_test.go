@@ -0,0 +1,66 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+)
+
+var _ = Describe("Rotation", func() {
+	// Credentials are Issued and Expire Relative to Now, an Expiry of 0 is None
+	DescribeTable("Waiting Before the Next Refresh",
+		func(rotation *containerregistryv1beta1.Rotation, issued time.Duration, expiry time.Duration, wait time.Duration) {
+			now := time.Now()
+			var expiration time.Time
+			if expiry != 0 {
+				expiration = now.Add(expiry)
+			}
+			Expect(refreshAfter(rotation, now.Add(issued), expiration)).To(BeNumerically("~", wait, time.Second))
+		},
+		Entry("Refreshes a Minute Before Expiry by Default", nil, time.Duration(0), time.Hour, 59*time.Minute),
+		Entry("Credentials Without an Expiry Last as Long as the Kubernetes Token", nil, time.Duration(0), time.Duration(0),
+			tokenExpirationSeconds*time.Second-defaultRefreshBefore),
+		Entry("Percentage of the Lifetime", &containerregistryv1beta1.Rotation{RefreshBefore: "25%"}, time.Duration(0), time.Hour, 45*time.Minute),
+		Entry("Duration", &containerregistryv1beta1.Rotation{RefreshBefore: "10m"}, time.Duration(0), time.Hour, 50*time.Minute),
+		Entry("Unparsable Refresh Before Falls Back to the Default", &containerregistryv1beta1.Rotation{RefreshBefore: "soon"}, time.Duration(0), time.Hour, 59*time.Minute),
+		Entry("Waits at Least the Minimum Interval",
+			&containerregistryv1beta1.Rotation{RefreshBefore: "10m", MinInterval: &metaV1.Duration{Duration: 5 * time.Minute}},
+			time.Duration(0), 12*time.Minute, 5*time.Minute),
+		Entry("Short Lived Credentials are not Kept Past their Expiry by the Minimum Interval", nil, time.Duration(0), 30*time.Second, 30*time.Second),
+		Entry("Short Lived Credentials are not Kept Past their Expiry by a Configured Minimum Interval",
+			&containerregistryv1beta1.Rotation{MinInterval: &metaV1.Duration{Duration: time.Hour}}, time.Duration(0), 10*time.Minute, 10*time.Minute),
+		Entry("Overdue Credentials are Refreshed After a Second", nil, -2*time.Hour, -time.Hour, time.Second),
+		Entry("Credentials Due for Rotation are Refreshed Before they Expire", nil, -time.Hour, 20*time.Second, 20*time.Second),
+	)
+
+	It("Should Only Ever Refresh Earlier with Jitter", func() {
+		now := time.Now()
+		rotation := &containerregistryv1beta1.Rotation{Jitter: "50%"}
+		for range 100 {
+			Expect(refreshAfter(rotation, now, now.Add(time.Hour))).To(And(
+				BeNumerically(">=", 29*time.Minute),
+				BeNumerically("<=", 59*time.Minute+time.Second),
+			))
+		}
+	})
+})
@@ -0,0 +1,144 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+)
+
+// Finalizer Removing the Secret from Linked Service Accounts Before an Auth is Deleted
+const serviceAccountFinalizer = "containerregistry.arthurvardevanyan.com/service-accounts"
+
+// tektonAnnotations Returns tekton.dev/docker-N Annotations for Each Registry
+func tektonAnnotations(registries []string) map[string]string {
+	annotations := map[string]string{}
+	for i, registry := range registries {
+		annotations[fmt.Sprintf("tekton.dev/docker-%d", i)] = "https://" + registry
+	}
+	return annotations
+}
+
+// serviceAccountsToLink Returns the Names of the Service Accounts Selected by Spec.LinkToServiceAccounts
+func (r *AuthReconciler) serviceAccountsToLink(reconcilerContext context.Context, containerRegistryAuth *containerregistryv1beta1.Auth) ([]string, error) {
+	link := containerRegistryAuth.Spec.LinkToServiceAccounts
+	if link == nil {
+		return nil, nil
+	}
+	if len(link.Names) == 0 && link.Selector == nil {
+		return []string{containerRegistryAuth.Spec.ServiceAccount}, nil
+	}
+
+	names := slices.Clone(link.Names)
+	if link.Selector != nil {
+		selector, err := metaV1.LabelSelectorAsSelector(link.Selector)
+		if err != nil {
+			return nil, err
+		}
+		var serviceAccounts coreV1.ServiceAccountList
+		if err = r.List(reconcilerContext, &serviceAccounts, client.InNamespace(containerRegistryAuth.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
+		}
+		for _, serviceAccount := range serviceAccounts.Items {
+			names = append(names, serviceAccount.Name)
+		}
+	}
+	slices.Sort(names)
+
+	return slices.Compact(names), nil
+}
+
+// patchServiceAccount Applies mutate to the Service Account, Service Accounts that do not Exist are Skipped
+func (r *AuthReconciler) patchServiceAccount(reconcilerContext context.Context, namespace string, name string, mutate func(*coreV1.ServiceAccount)) error {
+	serviceAccount := &coreV1.ServiceAccount{}
+	if err := r.Get(reconcilerContext, types.NamespacedName{Name: name, Namespace: namespace}, serviceAccount); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	original := serviceAccount.DeepCopy()
+	mutate(serviceAccount)
+
+	return client.IgnoreNotFound(r.Patch(reconcilerContext, serviceAccount, client.StrategicMergeFrom(original)))
+}
+
+func linkSecret(secretName string, tekton bool) func(*coreV1.ServiceAccount) {
+	return func(serviceAccount *coreV1.ServiceAccount) {
+		if !slices.ContainsFunc(serviceAccount.ImagePullSecrets, func(reference coreV1.LocalObjectReference) bool { return reference.Name == secretName }) {
+			serviceAccount.ImagePullSecrets = append(serviceAccount.ImagePullSecrets, coreV1.LocalObjectReference{Name: secretName})
+		}
+		if tekton && !slices.ContainsFunc(serviceAccount.Secrets, func(reference coreV1.ObjectReference) bool { return reference.Name == secretName }) {
+			serviceAccount.Secrets = append(serviceAccount.Secrets, coreV1.ObjectReference{Name: secretName})
+		}
+	}
+}
+
+func unlinkSecret(secretName string) func(*coreV1.ServiceAccount) {
+	return func(serviceAccount *coreV1.ServiceAccount) {
+		serviceAccount.ImagePullSecrets = slices.DeleteFunc(serviceAccount.ImagePullSecrets, func(reference coreV1.LocalObjectReference) bool { return reference.Name == secretName })
+		serviceAccount.Secrets = slices.DeleteFunc(serviceAccount.Secrets, func(reference coreV1.ObjectReference) bool { return reference.Name == secretName })
+	}
+}
+
+// linkServiceAccounts References the Secret from the Service Accounts Selected by Spec.LinkToServiceAccounts,
+// and Removes References Left Behind by Service Accounts that are no Longer Selected or a Renamed Secret.
+// The Status is Updated After Each Service Account, so a Failure Part Way Through Loses no Links.
+func (r *AuthReconciler) linkServiceAccounts(reconcilerContext context.Context, containerRegistryAuth *containerregistryv1beta1.Auth) error {
+	names, err := r.serviceAccountsToLink(reconcilerContext, containerRegistryAuth)
+	if err != nil {
+		return err
+	}
+	secretName := containerRegistryAuth.Spec.SecretName
+	links := &containerRegistryAuth.Status.ServiceAccountLinks
+
+	for _, name := range slices.Clone(links.ServiceAccounts) {
+		if links.SecretName == secretName && slices.Contains(names, name) {
+			continue
+		}
+		if err = r.patchServiceAccount(reconcilerContext, containerRegistryAuth.Namespace, name, unlinkSecret(links.SecretName)); err != nil {
+			return err
+		}
+		links.ServiceAccounts = slices.DeleteFunc(links.ServiceAccounts, func(linked string) bool { return linked == name })
+	}
+	// Every Service Account Left is Linked to the Current Secret
+	links.SecretName = ""
+	if len(links.ServiceAccounts) > 0 || len(names) > 0 {
+		links.SecretName = secretName
+	}
+	if len(links.ServiceAccounts) == 0 {
+		links.ServiceAccounts = nil
+	}
+
+	tekton := containerRegistryAuth.Spec.LinkToServiceAccounts != nil && containerRegistryAuth.Spec.LinkToServiceAccounts.Tekton
+	for _, name := range names {
+		if err = r.patchServiceAccount(reconcilerContext, containerRegistryAuth.Namespace, name, linkSecret(secretName, tekton)); err != nil {
+			return err
+		}
+		if !slices.Contains(links.ServiceAccounts, name) {
+			links.ServiceAccounts = append(links.ServiceAccounts, name)
+			slices.Sort(links.ServiceAccounts)
+		}
+	}
+
+	return nil
+}
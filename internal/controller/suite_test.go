@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -37,6 +38,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/mock"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/plugin"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/tracing"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -49,6 +53,7 @@ var k8sManager manager.Manager
 var testEnv *envtest.Environment
 var ctx context.Context
 var cancel context.CancelFunc
+var mockSigner *mock.Signer
 
 func TestControllers(t *testing.T) {
 	RegisterFailHandler(Fail)
@@ -61,6 +66,16 @@ var _ = BeforeSuite(func() {
 
 	ctx, cancel = context.WithCancel(context.TODO())
 
+	// TRACING_EXPORTER=stdout prints the test's spans, or otlp sends them to a local collector
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Options{
+		Exporter:    getEnv("TRACING_EXPORTER", tracing.ExporterNone),
+		Endpoint:    getEnv("TRACING_ENDPOINT", ""),
+		Insecure:    true,
+		SampleRatio: 1,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(shutdownTracing)
+
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
 		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
@@ -76,7 +91,6 @@ var _ = BeforeSuite(func() {
 			fmt.Sprintf("1.31.0-%s-%s", runtime.GOOS, runtime.GOARCH)),
 	}
 
-	var err error
 	// cfg is defined in this file globally.
 	cfg, err = testEnv.Start()
 	Expect(err).NotTo(HaveOccurred())
@@ -93,12 +107,37 @@ var _ = BeforeSuite(func() {
 
 	k8sManager, err = ctrl.NewManager(cfg, ctrl.Options{
 		Scheme: scheme.Scheme,
+		Cache:  CacheOptions(),
+		Client: ClientOptions(),
 	})
 	Expect(err).ToNot(HaveOccurred())
 
+	// A plugin that ignores its request and returns static credentials
+	pluginPath := filepath.Join(GinkgoT().TempDir(), "static-plugin")
+	err = os.WriteFile(pluginPath, []byte(`#!/bin/sh
+cat > /dev/null
+echo '{"kind":"PluginResponse","username":"plugin-user","password":"plugin-password","registries":["plugin.example.com"],"expiration":"2030-01-01T00:00:00Z"}'
+`), 0755)
+	Expect(err).NotTo(HaveOccurred())
+	mockSigner, err = mock.NewSigner()
+	Expect(err).NotTo(HaveOccurred())
+	providers := DefaultProviders(mockSigner)
+	providers.Register(plugin.Name, plugin.New(&plugin.Config{
+		Plugins: []plugin.PluginConfig{{Name: "static", Path: pluginPath}},
+	}))
+
 	err = (&AuthReconciler{
-		Client: k8sManager.GetClient(),
-		Scheme: k8sManager.GetScheme(),
+		Client:    k8sManager.GetClient(),
+		Scheme:    k8sManager.GetScheme(),
+		Providers: providers,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&ClusterAuthReconciler{
+		Client:    k8sManager.GetClient(),
+		Scheme:    k8sManager.GetScheme(),
+		Providers: providers,
+		Namespace: getEnv("OBJECT_NAMESPACE", "smoke-tests"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -0,0 +1,143 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/google"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/kubernetes"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// Field Indexes of Auths and ClusterAuths by the Objects they Reference in their Namespace
+const (
+	serviceAccountIndex = ".serviceAccounts"
+	configMapIndex      = ".configMaps"
+)
+
+// referencedServiceAccounts Returns the Service Account Tokens are Minted For, and the Service Accounts the Secret is Linked To
+func referencedServiceAccounts(containerRegistryAuth *containerregistryv1beta1.Auth) []string {
+	names := []string{containerRegistryAuth.Spec.ServiceAccount}
+	if link := containerRegistryAuth.Spec.LinkToServiceAccounts; link != nil {
+		names = append(names, link.Names...)
+	}
+	names = append(names, containerRegistryAuth.Status.ServiceAccountLinks.ServiceAccounts...)
+	slices.Sort(names)
+
+	return slices.Compact(names)
+}
+
+// referencedConfigMaps Returns the Config Maps Holding the Google Credential Configuration of Each Registry
+func referencedConfigMaps(containerRegistryAuth *containerregistryv1beta1.Auth) []string {
+	var names []string
+	for _, entry := range provider.Entries(containerRegistryAuth) {
+		spec := entry.Auth.Spec
+		switch {
+		case spec.ContainerRegistry == google.Name && spec.GoogleArtifactRegistry.Type == "configMap":
+			names = append(names, spec.GoogleArtifactRegistry.ObjectName)
+		case spec.ContainerRegistry == google.SecretManagerName && spec.GoogleSecretManager.Type == "configMap":
+			names = append(names, spec.GoogleSecretManager.ObjectName)
+		}
+	}
+	slices.Sort(names)
+
+	return slices.Compact(names)
+}
+
+// indexReferences Indexes the Objects of the Type by the Service Accounts and Config Maps their Auth References
+func indexReferences(mgr ctrl.Manager, object client.Object, toAuth func(client.Object) *containerregistryv1beta1.Auth) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(context.Background(), object, serviceAccountIndex, func(object client.Object) []string {
+		return referencedServiceAccounts(toAuth(object))
+	}); err != nil {
+		return err
+	}
+	return indexer.IndexField(context.Background(), object, configMapIndex, func(object client.Object) []string {
+		return referencedConfigMaps(toAuth(object))
+	})
+}
+
+// authsReferencing Requests the Auths in the Object's Namespace that Reference it by the Field Index
+func (r *AuthReconciler) authsReferencing(index string) handler.MapFunc {
+	return func(reconcilerContext context.Context, object client.Object) []reconcile.Request {
+		return r.listAuths(reconcilerContext, client.InNamespace(object.GetNamespace()), client.MatchingFields{index: object.GetName()})
+	}
+}
+
+// clusterAuthsReferencing Requests the ClusterAuths that Reference the Object in the Controller's Namespace by the Field Index
+func (r *ClusterAuthReconciler) clusterAuthsReferencing(index string) handler.MapFunc {
+	return func(reconcilerContext context.Context, object client.Object) []reconcile.Request {
+		if object.GetNamespace() != r.Namespace {
+			return nil
+		}
+
+		var clusterAuths containerregistryv1beta1.ClusterAuthList
+		if err := r.List(reconcilerContext, &clusterAuths, client.MatchingFields{index: object.GetName()}); err != nil {
+			log.FromContext(reconcilerContext).Error(err, "Unable to List Cluster Auth Objects")
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(clusterAuths.Items))
+		for _, clusterAuth := range clusterAuths.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&clusterAuth)})
+		}
+		return requests
+	}
+}
+
+// writtenByController Reports Whether the Secret Holds the Data the Controller Last Wrote to it
+func writtenByController(object client.Object) bool {
+	secret, ok := object.(*coreV1.Secret)
+	if !ok {
+		return false
+	}
+	dataHash := secret.Annotations[kubernetes.DataHashAnnotation]
+	return dataHash != "" && dataHash == kubernetes.DockerConfigHash(secret.Data[".dockerconfigjson"])
+}
+
+// secretChangedPredicate Ignores the Controller's Own Writes to Image Pull Secrets, which would Otherwise Requeue the Owner
+// at Once, Bypassing the Retry Backoff and Exchanging Credentials in a Loop. Deleted and Edited Secrets are Reconciled.
+var secretChangedPredicate = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool { return !writtenByController(e.Object) },
+	UpdateFunc: func(e event.UpdateEvent) bool { return !writtenByController(e.ObjectNew) },
+}
+
+// clusterAuthAsAuth Returns the Auth a ClusterAuth Exchanges its Credentials as, in the Controller's Namespace
+func clusterAuthAsAuth(clusterAuth *containerregistryv1beta1.ClusterAuth, namespace string) *containerregistryv1beta1.Auth {
+	return &containerregistryv1beta1.Auth{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:       clusterAuth.Name,
+			Namespace:  namespace,
+			Generation: clusterAuth.Generation,
+		},
+		Spec:   clusterAuth.Spec.AuthSpec,
+		Status: clusterAuth.Status.AuthStatus,
+	}
+}
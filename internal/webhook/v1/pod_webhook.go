@@ -0,0 +1,138 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/internal/controller"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/kubernetes"
+)
+
+// Set to "false" on a Namespace or Pod to Opt Out of Image Pull Secret Injection
+const InjectPullSecretsAnnotation = "containerregistry.arthurvardevanyan.com/inject-pull-secrets"
+
+// log is for logging in this package.
+var podlog = logf.Log.WithName("pod-resource")
+
+// SetupPodWebhookWithManager registers the webhook for Pods in the manager.
+func SetupPodWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&corev1.Pod{}).
+		WithDefaulter(&PodCustomDefaulter{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod-v1.containerregistry.arthurvardevanyan.com,admissionReviewVersions=v1
+
+// PodCustomDefaulter Injects the Secrets of Ready Auths Matching the Pod's Images into imagePullSecrets
+type PodCustomDefaulter struct {
+	Client client.Reader
+}
+
+var _ webhook.CustomDefaulter = &PodCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind Pod.
+func (d *PodCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return fmt.Errorf("expected a Pod object but got %T", obj)
+	}
+
+	// Pods Created by Controllers do not have a Namespace Yet
+	namespace := pod.Namespace
+	if namespace == "" {
+		req, err := admission.RequestFromContext(ctx)
+		if err != nil {
+			return err
+		}
+		namespace = req.Namespace
+	}
+
+	if pod.Annotations[InjectPullSecretsAnnotation] == "false" {
+		return nil
+	}
+	var podNamespace corev1.Namespace
+	if err := d.Client.Get(ctx, types.NamespacedName{Name: namespace}, &podNamespace); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if podNamespace.Annotations[InjectPullSecretsAnnotation] == "false" {
+		return nil
+	}
+
+	var auths containerregistryv1beta1.AuthList
+	if err := d.Client.List(ctx, &auths, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+
+	images := podImages(pod)
+	for _, auth := range auths.Items {
+		if !authReady(&auth) || !authMatchesImages(&auth, images) {
+			continue
+		}
+		if slices.ContainsFunc(pod.Spec.ImagePullSecrets, func(reference corev1.LocalObjectReference) bool { return reference.Name == auth.Spec.SecretName }) {
+			continue
+		}
+		podlog.V(1).Info("Injecting Image Pull Secret", "namespace", namespace, "secret", auth.Spec.SecretName)
+		pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: auth.Spec.SecretName})
+	}
+
+	return nil
+}
+
+// authReady Reports Whether the Auth's Secret Holds Usable Credentials
+// A Failed Refresh Leaves the Previously Written Secret in Place, so Only the SecretSynced Condition Counts
+func authReady(auth *containerregistryv1beta1.Auth) bool {
+	return auth.DeletionTimestamp.IsZero() && meta.IsStatusConditionTrue(auth.Status.Conditions, controller.ConditionSecretSynced) && len(auth.Status.Hosts) > 0
+}
+
+func authMatchesImages(auth *containerregistryv1beta1.Auth, images []string) bool {
+	for _, host := range auth.Status.Hosts {
+		for _, image := range images {
+			if kubernetes.RegistryMatchesImage(host, image) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func podImages(pod *corev1.Pod) []string {
+	var images []string
+	for _, container := range pod.Spec.InitContainers {
+		images = append(images, container.Image)
+	}
+	for _, container := range pod.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		images = append(images, container.Image)
+	}
+	return images
+}
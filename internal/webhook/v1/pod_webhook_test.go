@@ -0,0 +1,157 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/internal/controller"
+)
+
+var _ = Describe("Pod Webhook", func() {
+	const namespace = "smoke-tests"
+
+	var defaulter *PodCustomDefaulter
+
+	readyAuth := func(name string, secretName string, hosts ...string) *containerregistryv1beta1.Auth {
+		return &containerregistryv1beta1.Auth{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       containerregistryv1beta1.AuthSpec{SecretName: secretName},
+			Status: containerregistryv1beta1.AuthStatus{
+				Hosts:      hosts,
+				Conditions: []metav1.Condition{{Type: controller.ConditionSecretSynced, Status: metav1.ConditionTrue}},
+			},
+		}
+	}
+
+	pod := func(images ...string) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: namespace}}
+		for _, image := range images {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "test", Image: image})
+		}
+		return pod
+	}
+
+	setup := func(namespaceAnnotations map[string]string, objects ...runtime.Object) {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(containerregistryv1beta1.AddToScheme(scheme)).To(Succeed())
+		objects = append(objects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace, Annotations: namespaceAnnotations}})
+		defaulter = &PodCustomDefaulter{Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()}
+	}
+
+	Context("When Creating a Pod", func() {
+		It("Should Inject the Secrets of Ready Auths Matching the Pod's Images", func() {
+			failed := readyAuth("failed", "failed-secret", "quay.io")
+			failed.Status.Conditions[0].Status = metav1.ConditionFalse
+			failed.Status.Error = "Unable to Write Secret"
+			stale := readyAuth("stale", "stale-secret", "quay.io")
+			stale.Status.Error = "Unable to Exchange Token"
+			setup(nil,
+				readyAuth("quay", "quay-secret", "quay.io"),
+				readyAuth("gar", "gar-secret", "us-central1-docker.pkg.dev"),
+				readyAuth("pending", "pending-secret"),
+				failed,
+				stale,
+			)
+
+			obj := pod("quay.io/example/app:latest", "nginx")
+			obj.Spec.InitContainers = []corev1.Container{{Name: "init", Image: "us-central1-docker.pkg.dev/example/repo/init"}}
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+			Expect(obj.Spec.ImagePullSecrets).To(ConsistOf(
+				corev1.LocalObjectReference{Name: "quay-secret"},
+				corev1.LocalObjectReference{Name: "gar-secret"},
+				corev1.LocalObjectReference{Name: "stale-secret"},
+			))
+		})
+
+		It("Should Not Duplicate Secrets the Pod Already References", func() {
+			setup(nil, readyAuth("quay", "quay-secret", "quay.io"))
+
+			obj := pod("quay.io/example/app:latest")
+			obj.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "quay-secret"}}
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+			Expect(obj.Spec.ImagePullSecrets).To(HaveLen(1))
+		})
+
+		It("Should Respect the Pod Opt Out Annotation", func() {
+			setup(nil, readyAuth("quay", "quay-secret", "quay.io"))
+
+			obj := pod("quay.io/example/app:latest")
+			obj.Annotations = map[string]string{InjectPullSecretsAnnotation: "false"}
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+			Expect(obj.Spec.ImagePullSecrets).To(BeEmpty())
+		})
+
+		It("Should Respect the Namespace Opt Out Annotation", func() {
+			setup(map[string]string{InjectPullSecretsAnnotation: "false"}, readyAuth("quay", "quay-secret", "quay.io"))
+
+			obj := pod("quay.io/example/app:latest")
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+			Expect(obj.Spec.ImagePullSecrets).To(BeEmpty())
+		})
+
+		DescribeTable("Should Only Inject the Secret of an Auth whose Host Matches the Image",
+			func(host string, image string, injected bool) {
+				setup(nil, readyAuth("auth", "auth-secret", host))
+
+				obj := pod(image)
+				Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+				if injected {
+					Expect(obj.Spec.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: "auth-secret"}))
+				} else {
+					Expect(obj.Spec.ImagePullSecrets).To(BeEmpty())
+				}
+			},
+			Entry("Tagged Images", "quay.io", "quay.io/org/app:latest", true),
+			Entry("Images by Digest", "quay.io", "quay.io/org/app@sha256:0123", true),
+			Entry("Other Registries", "quay.io", "registry.io/org/app", false),
+			Entry("Hosts with a Scheme", "https://quay.io", "quay.io/org/app", true),
+			Entry("Hosts with a Scheme and Trailing Slash", "http://quay.io/", "quay.io/org/app", true),
+			Entry("Registries Sharing a Prefix", "quay.io", "quay.io.evil.com/org/app", false),
+			Entry("Hosts with a Port", "registry.local:5000", "registry.local:5000/app", true),
+			Entry("Images on Another Port", "registry.local", "registry.local:5000/app", false),
+			Entry("Hosts Without a Dot", "localhost", "localhost/app", true),
+			Entry("Path Prefixes", "quay.io/org", "quay.io/org/app", true),
+			Entry("Path Prefixes Equal to the Image", "quay.io/org", "quay.io/org", true),
+			Entry("Path Prefixes Match Whole Path Segments", "quay.io/org", "quay.io/organisation/app", false),
+			Entry("Tags Named Like the Path", "quay.io/org/app", "quay.io/org/app:org", true),
+			Entry("Path Prefixes with a Trailing Slash", "quay.io/org/", "quay.io/org/app", true),
+			Entry("Wildcard Subdomains", "*.gcr.io", "us.gcr.io/project/app", true),
+			Entry("Nested Wildcard Subdomains", "*.gcr.io", "eu.us.gcr.io/project/app", true),
+			Entry("Wildcards Need a Subdomain", "*.gcr.io", "gcr.io/project/app", false),
+			Entry("Wildcards Match Whole Labels", "*.gcr.io", "evilgcr.io/project/app", false),
+			Entry("Images Without a Host are on Docker Hub", "docker.io", "nginx", true),
+			Entry("Docker Hub Images in an Organisation", "docker.io", "library/nginx:1.27", true),
+			Entry("The Docker Hub Index", "https://index.docker.io/v1/", "nginx", true),
+			Entry("Images on the Docker Hub Index", "index.docker.io", "index.docker.io/library/nginx", true),
+			Entry("Official Images are Under library", "docker.io/library", "nginx", true),
+			Entry("Official Images with the Docker Hub Host", "docker.io/library", "docker.io/nginx:1.27", true),
+			Entry("Images in Other Docker Hub Organisations", "docker.io/library", "bitnami/nginx", false),
+			Entry("Docker Hub Images are not on Other Registries", "quay.io", "nginx", false),
+		)
+	})
+})
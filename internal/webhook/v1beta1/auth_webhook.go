@@ -0,0 +1,305 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/harbor"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/quay"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/tokenexchange"
+)
+
+// log is for logging in this package.
+var authlog = logf.Log.WithName("auth-resource")
+
+// SetupAuthWebhookWithManager registers the webhook for Auth in the manager.
+func SetupAuthWebhookWithManager(mgr ctrl.Manager, providers *provider.Registry) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&containerregistryv1beta1.Auth{}).
+		WithValidator(&AuthCustomValidator{Client: mgr.GetClient(), Providers: providers}).
+		WithDefaulter(&AuthCustomDefaulter{Providers: providers}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-containerregistry-arthurvardevanyan-com-v1beta1-auth,mutating=true,failurePolicy=fail,sideEffects=None,groups=containerregistry.arthurvardevanyan.com,resources=auths,verbs=create;update,versions=v1beta1,name=mauth-v1beta1.containerregistry.arthurvardevanyan.com,admissionReviewVersions=v1
+
+// AuthCustomDefaulter Fills in Provider Audiences and Normalises Quay URLs
+type AuthCustomDefaulter struct {
+	Providers *provider.Registry
+}
+
+var _ webhook.CustomDefaulter = &AuthCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind Auth.
+func (d *AuthCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	auth, ok := obj.(*containerregistryv1beta1.Auth)
+	if !ok {
+		return fmt.Errorf("expected an Auth object but got %T", obj)
+	}
+	authlog.V(1).Info("Defaulting for Auth", "name", auth.GetName())
+
+	auth.Spec.Quay.URL = quay.NormalizeURL(auth.Spec.Quay.URL)
+	for i := range auth.Spec.Registries {
+		auth.Spec.Registries[i].Quay.URL = quay.NormalizeURL(auth.Spec.Registries[i].Quay.URL)
+	}
+
+	if len(auth.Spec.Registries) == 0 {
+		if len(auth.Spec.Audiences) == 0 {
+			auth.Spec.Audiences = d.defaultAudiences(auth)
+		}
+		return nil
+	}
+
+	entries := provider.Entries(auth)
+	for i := range auth.Spec.Registries {
+		if len(auth.Spec.Registries[i].Audiences) == 0 {
+			auth.Spec.Registries[i].Audiences = d.defaultAudiences(entries[i].Auth)
+		}
+	}
+	if len(auth.Spec.Audiences) == 0 {
+		auth.Spec.Audiences = auth.Spec.Registries[0].Audiences
+	}
+
+	return nil
+}
+
+func (d *AuthCustomDefaulter) defaultAudiences(auth *containerregistryv1beta1.Auth) []string {
+	registryProvider, err := d.Providers.Get(auth.Spec.ContainerRegistry)
+	if err != nil {
+		return nil
+	}
+	if audienceDefaulter, ok := registryProvider.(provider.AudienceDefaulter); ok {
+		return audienceDefaulter.DefaultAudiences(auth)
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-containerregistry-arthurvardevanyan-com-v1beta1-auth,mutating=false,failurePolicy=fail,sideEffects=None,groups=containerregistry.arthurvardevanyan.com,resources=auths,verbs=create;update,versions=v1beta1,name=vauth-v1beta1.containerregistry.arthurvardevanyan.com,admissionReviewVersions=v1
+
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// AuthCustomValidator Rejects Auths their Providers would Fail to Validate at Reconcile Time,
+// and Auths for Service Accounts the Requesting User could not Mint Tokens for Themselves
+type AuthCustomValidator struct {
+	Client    client.Client
+	Providers *provider.Registry
+}
+
+var _ webhook.CustomValidator = &AuthCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Auth.
+func (v *AuthCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	auth, ok := obj.(*containerregistryv1beta1.Auth)
+	if !ok {
+		return nil, fmt.Errorf("expected an Auth object but got %T", obj)
+	}
+	authlog.V(1).Info("Validation for Auth upon creation", "name", auth.GetName())
+
+	if err := toInvalid("Auth", auth.Name, v.validate(auth)); err != nil {
+		return nil, err
+	}
+	return nil, v.authorize(ctx, auth, "Auth")
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Auth.
+func (v *AuthCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	auth, ok := newObj.(*containerregistryv1beta1.Auth)
+	if !ok {
+		return nil, fmt.Errorf("expected an Auth object for the newObj but got %T", newObj)
+	}
+	authlog.V(1).Info("Validation for Auth upon update", "name", auth.GetName())
+
+	// Allow Auths Created Before the Webhook to be Deleted
+	if !auth.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+
+	if err := toInvalid("Auth", auth.Name, v.validate(auth)); err != nil {
+		return nil, err
+	}
+	return nil, v.authorize(ctx, auth, "Auth")
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Auth.
+func (v *AuthCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// authorize Checks the Requesting User can Create Tokens for Spec.ServiceAccount, as the Controller Mints them on the Auth's Behalf,
+// and can Read the Secrets the Providers Send to the Endpoints Set in the Auth. kind is the Kind of the Object Being Admitted.
+func (v *AuthCustomValidator) authorize(ctx context.Context, auth *containerregistryv1beta1.Auth, kind string) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	namespace := auth.Namespace
+	if namespace == "" {
+		namespace = req.Namespace
+	}
+	groupResource := containerregistryv1beta1.GroupVersion.WithResource(strings.ToLower(kind) + "s").GroupResource()
+
+	allowed, err := v.review(ctx, req, authorizationv1.ResourceAttributes{
+		Namespace:   namespace,
+		Verb:        "create",
+		Resource:    "serviceaccounts",
+		Subresource: "token",
+		Name:        auth.Spec.ServiceAccount,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to review access to service account '%s': %w", auth.Spec.ServiceAccount, err)
+	}
+	if !allowed {
+		return apierrors.NewForbidden(groupResource, auth.Name,
+			fmt.Errorf("user '%s' cannot create serviceaccounts/token for service account '%s' in namespace '%s', "+
+				"which is required to use it in the %s", req.UserInfo.Username, auth.Spec.ServiceAccount, namespace, kind))
+	}
+
+	for _, secretName := range referencedSecrets(auth) {
+		allowed, err = v.review(ctx, req, authorizationv1.ResourceAttributes{
+			Namespace: namespace,
+			Verb:      "get",
+			Resource:  "secrets",
+			Name:      secretName,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to review access to secret '%s': %w", secretName, err)
+		}
+		if !allowed {
+			return apierrors.NewForbidden(groupResource, auth.Name,
+				fmt.Errorf("user '%s' cannot get secret '%s' in namespace '%s', "+
+					"which is required to use it in the %s", req.UserInfo.Username, secretName, namespace, kind))
+		}
+	}
+	return nil
+}
+
+// review Asks the API Server Whether the Requesting User is Allowed the Access
+func (v *AuthCustomValidator) review(ctx context.Context, req admission.Request, attributes authorizationv1.ResourceAttributes) (bool, error) {
+	extra := map[string]authorizationv1.ExtraValue{}
+	for key, value := range req.UserInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	subjectAccessReview := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:               req.UserInfo.Username,
+			Groups:             req.UserInfo.Groups,
+			UID:                req.UserInfo.UID,
+			Extra:              extra,
+			ResourceAttributes: &attributes,
+		},
+	}
+	if err := v.Client.Create(ctx, subjectAccessReview); err != nil {
+		return false, err
+	}
+	return subjectAccessReview.Status.Allowed, nil
+}
+
+// referencedSecrets Returns the Secrets in the Auth's Namespace the Providers Read Credentials From
+func referencedSecrets(auth *containerregistryv1beta1.Auth) []string {
+	var names []string
+	for _, entry := range provider.Entries(auth) {
+		spec := entry.Auth.Spec
+		switch spec.ContainerRegistry {
+		case tokenexchange.Name:
+			if spec.TokenExchange.ClientAuth != nil {
+				names = append(names, spec.TokenExchange.ClientAuth.SecretName)
+			}
+		case harbor.Name:
+			names = append(names, spec.Harbor.AdminSecretName)
+		}
+	}
+	slices.Sort(names)
+
+	return slices.Compact(names)
+}
+
+func (v *AuthCustomValidator) validate(auth *containerregistryv1beta1.Auth) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	for _, msg := range validation.IsDNS1123Subdomain(auth.Spec.SecretName) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("secretName"), auth.Spec.SecretName, msg))
+	}
+	for _, msg := range validation.IsDNS1123Subdomain(auth.Spec.ServiceAccount) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("serviceAccount"), auth.Spec.ServiceAccount, msg))
+	}
+	allErrs = append(allErrs, validateAudiences(auth.Spec.Audiences, specPath.Child("audiences"), true)...)
+
+	if len(auth.Spec.Registries) == 0 {
+		return append(allErrs, v.validateProvider(auth, specPath)...)
+	}
+	for i, entry := range provider.Entries(auth) {
+		registryPath := specPath.Child("registries").Index(i)
+		allErrs = append(allErrs, validateAudiences(auth.Spec.Registries[i].Audiences, registryPath.Child("audiences"), false)...)
+		allErrs = append(allErrs, v.validateProvider(entry.Auth, registryPath)...)
+	}
+
+	return allErrs
+}
+
+func toInvalid(kind string, name string, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(containerregistryv1beta1.GroupVersion.WithKind(kind).GroupKind(), name, allErrs)
+}
+
+func (v *AuthCustomValidator) validateProvider(auth *containerregistryv1beta1.Auth, path *field.Path) field.ErrorList {
+	registryProvider, err := v.Providers.Get(auth.Spec.ContainerRegistry)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path.Child("containerRegistry"), auth.Spec.ContainerRegistry, err.Error())}
+	}
+	if err = registryProvider.Validate(auth); err != nil {
+		return field.ErrorList{field.Invalid(path.Child(auth.Spec.ContainerRegistry), auth.Spec.ContainerRegistry, err.Error())}
+	}
+	return nil
+}
+
+// validateAudiences Rejects Blank and Duplicate Audiences, and Missing Ones when Required
+func validateAudiences(audiences []string, path *field.Path, required bool) field.ErrorList {
+	var allErrs field.ErrorList
+	if required && len(audiences) == 0 {
+		allErrs = append(allErrs, field.Required(path, "at least one audience is required"))
+	}
+	seen := sets.New[string]()
+	for i, audience := range audiences {
+		if audience == "" {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), audience, "must not be empty"))
+		} else if seen.Has(audience) {
+			allErrs = append(allErrs, field.Duplicate(path.Index(i), audience))
+		}
+		seen.Insert(audience)
+	}
+	return allErrs
+}
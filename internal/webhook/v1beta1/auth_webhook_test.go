@@ -0,0 +1,255 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/aws"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/google"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/harbor"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/quay"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/tokenexchange"
+)
+
+var _ = Describe("Auth Webhook", func() {
+	var (
+		auth      *containerregistryv1beta1.Auth
+		defaulter *AuthCustomDefaulter
+		validator *AuthCustomValidator
+		// Answer of the Fake SubjectAccessReviews, Reviews of deniedResource are Always Denied, and the Last Review Made
+		allowed             bool
+		deniedResource      string
+		subjectAccessReview *authorizationv1.SubjectAccessReview
+		ctx                 context.Context
+	)
+
+	BeforeEach(func() {
+		providers := provider.NewRegistry()
+		providers.Register(quay.Name, quay.Provider{})
+		providers.Register(google.Name, google.Provider{})
+		providers.Register(aws.Name, aws.Provider{})
+		providers.Register(tokenexchange.Name, tokenexchange.Provider{})
+		providers.Register(harbor.Name, harbor.Provider{})
+		defaulter = &AuthCustomDefaulter{Providers: providers}
+
+		allowed = true
+		deniedResource = ""
+		subjectAccessReview = nil
+		fakeClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				review, ok := obj.(*authorizationv1.SubjectAccessReview)
+				if !ok {
+					return c.Create(ctx, obj, opts...)
+				}
+				review.Status.Allowed = allowed && review.Spec.ResourceAttributes.Resource != deniedResource
+				subjectAccessReview = review
+				return nil
+			},
+		}).Build()
+		validator = &AuthCustomValidator{Client: fakeClient, Providers: providers}
+
+		ctx = admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Namespace: "smoke-tests",
+				UserInfo: authenticationv1.UserInfo{
+					Username: "developer",
+					Groups:   []string{"system:authenticated"},
+				},
+			},
+		})
+
+		auth = &containerregistryv1beta1.Auth{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "smoke-tests"},
+			Spec: containerregistryv1beta1.AuthSpec{
+				SecretName:        "container-registry-auth-test",
+				ServiceAccount:    "wif-test",
+				Audiences:         []string{"openshift"},
+				ContainerRegistry: "quay",
+				Quay: containerregistryv1beta1.Quay{
+					RobotAccount: "arthurvardevanyan+push",
+					URL:          "quay.io",
+				},
+			},
+		}
+	})
+
+	Context("When Creating an Auth under the Defaulting Webhook", func() {
+		It("Should Normalise the Quay URL", func() {
+			auth.Spec.Quay.URL = "https://Quay.io/"
+			Expect(defaulter.Default(context.Background(), auth)).To(Succeed())
+			Expect(auth.Spec.Quay.URL).To(Equal("quay.io"))
+		})
+
+		It("Should Default the Audiences of the Provider", func() {
+			auth.Spec.Audiences = nil
+			auth.Spec.ContainerRegistry = "googleArtifactRegistry"
+			auth.Spec.GoogleArtifactRegistry = containerregistryv1beta1.GoogleArtifactRegistry{
+				RegistryLocation:     "us-central1",
+				Type:                 "inline",
+				GoogleServiceAccount: "wif-test@example.iam.gserviceaccount.com",
+				GooglePoolProject:    "123456789",
+				GooglePoolName:       "pool",
+				GoogleProviderName:   "provider",
+			}
+			Expect(defaulter.Default(context.Background(), auth)).To(Succeed())
+			Expect(auth.Spec.Audiences).To(Equal([]string{
+				"https://iam.googleapis.com/projects/123456789/locations/global/workloadIdentityPools/pool/providers/provider",
+			}))
+		})
+
+		It("Should Default the Audiences of Each Registry", func() {
+			auth.Spec.Audiences = nil
+			auth.Spec.Registries = []containerregistryv1beta1.Registry{{
+				Name:              "ecr",
+				ContainerRegistry: "awsElasticContainerRegistry",
+			}}
+			Expect(defaulter.Default(context.Background(), auth)).To(Succeed())
+			Expect(auth.Spec.Registries[0].Audiences).To(Equal([]string{"sts.amazonaws.com"}))
+			Expect(auth.Spec.Audiences).To(Equal([]string{"sts.amazonaws.com"}))
+		})
+
+		It("Should Keep Audiences that are Set", func() {
+			auth.Spec.ContainerRegistry = "awsElasticContainerRegistry"
+			Expect(defaulter.Default(context.Background(), auth)).To(Succeed())
+			Expect(auth.Spec.Audiences).To(Equal([]string{"openshift"}))
+		})
+	})
+
+	Context("When Creating or Updating an Auth under the Validating Webhook", func() {
+		It("Should Admit a Valid Auth", func() {
+			Expect(validator.ValidateCreate(ctx, auth)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should Deny a Quay Auth Without a Robot Account", func() {
+			auth.Spec.Quay.RobotAccount = ""
+			Expect(validator.ValidateCreate(ctx, auth)).Error().To(MatchError(ContainSubstring("quay.robotAccount is required")))
+		})
+
+		It("Should Deny Robot Accounts Not in org+robot Form", func() {
+			auth.Spec.Quay.RobotAccount = "push"
+			Expect(validator.ValidateCreate(ctx, auth)).Error().To(MatchError(ContainSubstring("must be in the form org+robot")))
+		})
+
+		It("Should Deny an Inline Google Auth Without a Pool Project", func() {
+			auth.Spec.ContainerRegistry = "googleArtifactRegistry"
+			auth.Spec.GoogleArtifactRegistry = containerregistryv1beta1.GoogleArtifactRegistry{
+				RegistryLocation:     "us-central1",
+				Type:                 "inline",
+				GoogleServiceAccount: "wif-test@example.iam.gserviceaccount.com",
+				GooglePoolName:       "pool",
+				GoogleProviderName:   "provider",
+			}
+			Expect(validator.ValidateUpdate(ctx, auth, auth)).Error().To(MatchError(ContainSubstring("googlePoolProject")))
+		})
+
+		It("Should Deny a Token Exchange Auth Without Registries", func() {
+			auth.Spec.ContainerRegistry = tokenexchange.Name
+			auth.Spec.TokenExchange = containerregistryv1beta1.TokenExchange{
+				TokenEndpoint: "https://sts.example.com/token",
+			}
+			Expect(validator.ValidateCreate(ctx, auth)).Error().To(MatchError(ContainSubstring("tokenExchange.registries is required")))
+		})
+
+		It("Should Deny Missing, Blank and Duplicate Audiences", func() {
+			auth.Spec.Audiences = nil
+			Expect(validator.ValidateCreate(ctx, auth)).Error().To(MatchError(ContainSubstring("spec.audiences: Required value")))
+
+			auth.Spec.Audiences = []string{"openshift", "", "openshift"}
+			_, err := validator.ValidateCreate(ctx, auth)
+			Expect(err).To(MatchError(ContainSubstring("spec.audiences[1]")))
+			Expect(err).To(MatchError(ContainSubstring("spec.audiences[2]: Duplicate value")))
+		})
+
+		It("Should Deny Unsupported Registries Listed in Registries", func() {
+			auth.Spec.Registries = []containerregistryv1beta1.Registry{{
+				Name:              "azure",
+				ContainerRegistry: "azureContainerRegistry",
+			}}
+			Expect(validator.ValidateCreate(ctx, auth)).Error().To(MatchError(ContainSubstring("spec.registries[0].containerRegistry")))
+		})
+
+		It("Should Review the User's Access to Create Tokens for the Service Account", func() {
+			Expect(validator.ValidateCreate(ctx, auth)).Error().NotTo(HaveOccurred())
+			Expect(subjectAccessReview).NotTo(BeNil())
+			Expect(subjectAccessReview.Spec.User).To(Equal("developer"))
+			Expect(subjectAccessReview.Spec.Groups).To(Equal([]string{"system:authenticated"}))
+			Expect(*subjectAccessReview.Spec.ResourceAttributes).To(Equal(authorizationv1.ResourceAttributes{
+				Namespace:   "smoke-tests",
+				Verb:        "create",
+				Resource:    "serviceaccounts",
+				Subresource: "token",
+				Name:        "wif-test",
+			}))
+		})
+
+		It("Should Deny Users that Cannot Create Tokens for the Service Account", func() {
+			allowed = false
+			_, err := validator.ValidateUpdate(ctx, auth, auth)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("user 'developer' cannot create serviceaccounts/token for service account 'wif-test' in namespace 'smoke-tests'")))
+		})
+
+		It("Should Review the User's Access to the Secrets the Providers Read", func() {
+			auth.Spec.ContainerRegistry = tokenexchange.Name
+			auth.Spec.TokenExchange = containerregistryv1beta1.TokenExchange{
+				TokenEndpoint: "https://sts.example.com/token",
+				Registries:    []string{"registry.example.com"},
+				ClientAuth:    &containerregistryv1beta1.TokenExchangeClientAuth{SecretName: "client-credentials"},
+			}
+			Expect(validator.ValidateCreate(ctx, auth)).Error().NotTo(HaveOccurred())
+			Expect(*subjectAccessReview.Spec.ResourceAttributes).To(Equal(authorizationv1.ResourceAttributes{
+				Namespace: "smoke-tests",
+				Verb:      "get",
+				Resource:  "secrets",
+				Name:      "client-credentials",
+			}))
+
+			deniedResource = "secrets"
+			_, err := validator.ValidateCreate(ctx, auth)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("user 'developer' cannot get secret 'client-credentials' in namespace 'smoke-tests'")))
+		})
+
+		It("Should Deny Users that Cannot Get the Harbor Admin Secret", func() {
+			auth.Spec.ContainerRegistry = harbor.Name
+			auth.Spec.Harbor = containerregistryv1beta1.Harbor{
+				URL:             "harbor.example.com",
+				Project:         "smoke-tests",
+				AdminSecretName: "harbor-admin",
+			}
+			deniedResource = "secrets"
+			_, err := validator.ValidateCreate(ctx, auth)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("user 'developer' cannot get secret 'harbor-admin' in namespace 'smoke-tests'")))
+		})
+	})
+})
@@ -0,0 +1,102 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// log is for logging in this package.
+var clusterauthlog = logf.Log.WithName("clusterauth-resource")
+
+// SetupClusterAuthWebhookWithManager registers the webhook for ClusterAuth in the manager.
+// namespace is the Controller's Namespace, where ClusterAuths Mint their Credentials.
+func SetupClusterAuthWebhookWithManager(mgr ctrl.Manager, providers *provider.Registry, namespace string) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&containerregistryv1beta1.ClusterAuth{}).
+		WithValidator(&ClusterAuthCustomValidator{
+			AuthCustomValidator: AuthCustomValidator{Client: mgr.GetClient(), Providers: providers},
+			Namespace:           namespace,
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-containerregistry-arthurvardevanyan-com-v1beta1-clusterauth,mutating=false,failurePolicy=fail,sideEffects=None,groups=containerregistry.arthurvardevanyan.com,resources=clusterauths,verbs=create;update,versions=v1beta1,name=vclusterauth-v1beta1.containerregistry.arthurvardevanyan.com,admissionReviewVersions=v1
+
+// ClusterAuthCustomValidator Rejects ClusterAuths as AuthCustomValidator Rejects Auths. Their Credentials are Minted
+// with the Service Account and Secrets in the Controller's Namespace, so the Requesting User Needs Access to them There.
+type ClusterAuthCustomValidator struct {
+	AuthCustomValidator
+	// The Controller's Namespace
+	Namespace string
+}
+
+var _ webhook.CustomValidator = &ClusterAuthCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ClusterAuth.
+func (v *ClusterAuthCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	clusterAuth, ok := obj.(*containerregistryv1beta1.ClusterAuth)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterAuth object but got %T", obj)
+	}
+	clusterauthlog.V(1).Info("Validation for ClusterAuth upon creation", "name", clusterAuth.GetName())
+
+	return nil, v.validateClusterAuth(ctx, clusterAuth)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ClusterAuth.
+func (v *ClusterAuthCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	clusterAuth, ok := newObj.(*containerregistryv1beta1.ClusterAuth)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterAuth object for the newObj but got %T", newObj)
+	}
+	clusterauthlog.V(1).Info("Validation for ClusterAuth upon update", "name", clusterAuth.GetName())
+
+	// Allow ClusterAuths Created Before the Webhook to be Deleted
+	if !clusterAuth.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+
+	return nil, v.validateClusterAuth(ctx, clusterAuth)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ClusterAuth.
+func (v *ClusterAuthCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateClusterAuth Validates and Authorizes the ClusterAuth as an Auth in the Controller's Namespace
+func (v *ClusterAuthCustomValidator) validateClusterAuth(ctx context.Context, clusterAuth *containerregistryv1beta1.ClusterAuth) error {
+	auth := &containerregistryv1beta1.Auth{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterAuth.Name, Namespace: v.Namespace},
+		Spec:       clusterAuth.Spec.AuthSpec,
+	}
+	if err := toInvalid("ClusterAuth", clusterAuth.Name, v.validate(auth)); err != nil {
+		return err
+	}
+	return v.authorize(ctx, auth, "ClusterAuth")
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/harbor"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/quay"
+)
+
+var _ = Describe("ClusterAuth Webhook", func() {
+	var (
+		clusterAuth *containerregistryv1beta1.ClusterAuth
+		validator   *ClusterAuthCustomValidator
+		// Answer of the Fake SubjectAccessReviews, Reviews of deniedResource are Always Denied, and the Last Review Made
+		allowed             bool
+		deniedResource      string
+		subjectAccessReview *authorizationv1.SubjectAccessReview
+		ctx                 context.Context
+	)
+
+	BeforeEach(func() {
+		providers := provider.NewRegistry()
+		providers.Register(quay.Name, quay.Provider{})
+		providers.Register(harbor.Name, harbor.Provider{})
+
+		allowed = true
+		deniedResource = ""
+		subjectAccessReview = nil
+		fakeClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				review, ok := obj.(*authorizationv1.SubjectAccessReview)
+				if !ok {
+					return c.Create(ctx, obj, opts...)
+				}
+				review.Status.Allowed = allowed && review.Spec.ResourceAttributes.Resource != deniedResource
+				subjectAccessReview = review
+				return nil
+			},
+		}).Build()
+		validator = &ClusterAuthCustomValidator{
+			AuthCustomValidator: AuthCustomValidator{Client: fakeClient, Providers: providers},
+			Namespace:           "container-registry-k8s-auth-controller-system",
+		}
+
+		ctx = admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{
+					Username: "developer",
+					Groups:   []string{"system:authenticated"},
+				},
+			},
+		})
+
+		clusterAuth = &containerregistryv1beta1.ClusterAuth{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: containerregistryv1beta1.ClusterAuthSpec{
+				AuthSpec: containerregistryv1beta1.AuthSpec{
+					SecretName:        "container-registry-auth-test",
+					ServiceAccount:    "wif-test",
+					Audiences:         []string{"openshift"},
+					ContainerRegistry: "quay",
+					Quay: containerregistryv1beta1.Quay{
+						RobotAccount: "arthurvardevanyan+push",
+						URL:          "quay.io",
+					},
+				},
+			},
+		}
+	})
+
+	Context("When Creating or Updating a ClusterAuth under the Validating Webhook", func() {
+		It("Should Admit a Valid ClusterAuth", func() {
+			Expect(validator.ValidateCreate(ctx, clusterAuth)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should Deny a ClusterAuth its Provider would Reject", func() {
+			clusterAuth.Spec.Quay.RobotAccount = "push"
+			_, err := validator.ValidateCreate(ctx, clusterAuth)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("ClusterAuth.containerregistry.arthurvardevanyan.com \"test\" is invalid")))
+		})
+
+		It("Should Review the User's Access to Create Tokens for the Service Account in the Controller's Namespace", func() {
+			Expect(validator.ValidateCreate(ctx, clusterAuth)).Error().NotTo(HaveOccurred())
+			Expect(subjectAccessReview).NotTo(BeNil())
+			Expect(subjectAccessReview.Spec.User).To(Equal("developer"))
+			Expect(*subjectAccessReview.Spec.ResourceAttributes).To(Equal(authorizationv1.ResourceAttributes{
+				Namespace:   "container-registry-k8s-auth-controller-system",
+				Verb:        "create",
+				Resource:    "serviceaccounts",
+				Subresource: "token",
+				Name:        "wif-test",
+			}))
+		})
+
+		It("Should Deny Users that Cannot Create Tokens for the Service Account", func() {
+			allowed = false
+			_, err := validator.ValidateUpdate(ctx, clusterAuth, clusterAuth)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("clusterauths.containerregistry.arthurvardevanyan.com \"test\" is forbidden")))
+			Expect(err).To(MatchError(ContainSubstring("user 'developer' cannot create serviceaccounts/token for service account 'wif-test' " +
+				"in namespace 'container-registry-k8s-auth-controller-system', which is required to use it in the ClusterAuth")))
+		})
+
+		It("Should Deny Users that Cannot Get the Secrets the Providers Read", func() {
+			clusterAuth.Spec.ContainerRegistry = harbor.Name
+			clusterAuth.Spec.Harbor = containerregistryv1beta1.Harbor{
+				URL:             "harbor.example.com",
+				Project:         "smoke-tests",
+				AdminSecretName: "harbor-admin",
+			}
+			deniedResource = "secrets"
+			_, err := validator.ValidateCreate(ctx, clusterAuth)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("user 'developer' cannot get secret 'harbor-admin' in namespace 'container-registry-k8s-auth-controller-system'")))
+		})
+
+		It("Should Allow ClusterAuths Being Deleted to be Updated", func() {
+			allowed = false
+			now := metav1.Now()
+			clusterAuth.DeletionTimestamp = &now
+			Expect(validator.ValidateUpdate(ctx, clusterAuth, clusterAuth)).Error().NotTo(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,95 @@
+package artifactory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+type AccessToken struct {
+	Token      string
+	UserName   string
+	Expiration time.Time
+}
+
+// BaseURL adds https:// to URLs without a scheme
+func BaseURL(url string) string {
+	if !strings.Contains(url, "://") {
+		url = "https://" + url
+	}
+	return strings.TrimSuffix(url, "/")
+}
+
+// Host of the URL, without the scheme or path
+func Host(url string) string {
+	host := BaseURL(url)
+	_, host, _ = strings.Cut(host, "://")
+	host, _, _ = strings.Cut(host, "/")
+	return host
+}
+
+// GetOIDCToken exchanges an external JWT for an Artifactory access token
+// https://jfrog.com/help/r/jfrog-rest-apis/exchange-oidc-token
+func GetOIDCToken(ctx context.Context, url string, providerName string, projectKey string, subjectToken string) (*AccessToken, error) {
+	request := map[string]string{
+		"grant_type":         "urn:ietf:params:oauth:grant-type:token-exchange",
+		"subject_token_type": "urn:ietf:params:oauth:token-type:id_token",
+		"subject_token":      subjectToken,
+		"provider_name":      providerName,
+	}
+	if projectKey != "" {
+		request["project_key"] = projectKey
+	}
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", BaseURL(url)+"/access/api/v1/oidc/token", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := provider.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, provider.NewHTTPError(resp, body)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		Username    string `json:"username"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal artifactory response. Error: %v", err)
+	}
+	if result.AccessToken == "" {
+		return nil, fmt.Errorf("artifactory response did not contain an access token")
+	}
+
+	accessToken := &AccessToken{
+		Token:    result.AccessToken,
+		UserName: result.Username,
+	}
+	if result.ExpiresIn > 0 {
+		accessToken.Expiration = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second).UTC()
+	}
+	return accessToken, nil
+}
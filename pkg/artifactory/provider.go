@@ -0,0 +1,66 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/jwt"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// Name is the Spec.ContainerRegistry value handled by Provider
+const Name = "artifactory"
+
+// Provider exchanges a Kubernetes token for an Artifactory access token via an OIDC integration
+type Provider struct{}
+
+func (Provider) Validate(auth *containerregistryv1beta1.Auth) error {
+	if auth.Spec.Artifactory.URL == "" {
+		return fmt.Errorf("artifactory.url is required")
+	}
+	if auth.Spec.Artifactory.ProviderName == "" {
+		return fmt.Errorf("artifactory.providerName is required")
+	}
+	return nil
+}
+
+func (Provider) Exchange(ctx context.Context, request provider.Request) (*provider.Credentials, error) {
+	artifactorySpec := request.Auth.Spec.Artifactory
+
+	accessToken, err := GetOIDCToken(ctx, artifactorySpec.URL, artifactorySpec.ProviderName, artifactorySpec.ProjectKey, request.SubjectToken)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Generate Artifactory Token: %w", err)
+	}
+
+	// Older Artifactory versions omit the username and expiry, fall back to the token's claims
+	// The subject is formatted as jfac@<service id>/users/<username>
+	userName := accessToken.UserName
+	if userName == "" {
+		subject, err := jwt.Subject(accessToken.Token)
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine artifactory username. Error: %v", err)
+		}
+		userName = path.Base(subject)
+	}
+	expiration := accessToken.Expiration
+	if expiration.IsZero() {
+		expiration, err = jwt.Expiration(accessToken.Token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	registry := artifactorySpec.Registry
+	if registry == "" {
+		registry = Host(artifactorySpec.URL)
+	}
+
+	return &provider.Credentials{
+		Username:   userName,
+		Password:   accessToken.Token,
+		Registries: []string{registry},
+		Expiration: expiration,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package aws
+
+import (
+	"context"
+	b64 "encoding/base64"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+type AuthorizationData struct {
+	AuthorizationToken string  `json:"authorizationToken"`
+	ExpiresAt          float64 `json:"expiresAt"`
+	ProxyEndpoint      string  `json:"proxyEndpoint"`
+}
+
+// Username and Password decoded from the base64 user:password Authorization Token
+func (r *AuthorizationData) Credentials() (string, string, error) {
+	decoded, err := b64.StdEncoding.DecodeString(r.AuthorizationToken)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to decode ecr authorization token. Error: %v", err)
+	}
+	userName, password, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return "", "", fmt.Errorf("invalid ecr authorization token format")
+	}
+	return userName, password, nil
+}
+
+// Registry host of the Proxy Endpoint, without the scheme
+func (r *AuthorizationData) Registry() string {
+	return strings.TrimPrefix(strings.TrimPrefix(r.ProxyEndpoint, "https://"), "http://")
+}
+
+func (r *AuthorizationData) Expiration() time.Time {
+	seconds, fraction := math.Modf(r.ExpiresAt)
+	return time.Unix(int64(seconds), int64(fraction*1e9)).UTC()
+}
+
+func ECREndpoint(region string) string {
+	return "https://api.ecr." + region + ".amazonaws.com"
+}
+
+// GetAuthorizationToken requests a registry token for the given accounts, or the caller's account if none are given.
+func GetAuthorizationToken(ctx context.Context, endpoint string, region string, credentials *Credentials, registryIDs []string) ([]AuthorizationData, error) {
+	request := map[string][]string{}
+	if len(registryIDs) > 0 {
+		request["registryIds"] = registryIDs
+	}
+
+	var result struct {
+		AuthorizationData []AuthorizationData `json:"authorizationData"`
+	}
+	err := callJSON(ctx, endpoint, region, "ecr", "AmazonEC2ContainerRegistry_V20150921.GetAuthorizationToken", credentials, request, &result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.AuthorizationData) == 0 {
+		return nil, fmt.Errorf("ecr response did not contain authorization data")
+	}
+
+	return result.AuthorizationData, nil
+}
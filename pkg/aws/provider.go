@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// Name is the Spec.ContainerRegistry value handled by Provider
+const Name = "awsElasticContainerRegistry"
+
+var invalidSessionNameCharacters = regexp.MustCompile(`[^\w+=,.@-]`)
+
+// Provider exchanges a Kubernetes token for an ECR authorization token via STS AssumeRoleWithWebIdentity
+type Provider struct{}
+
+func (Provider) Validate(auth *containerregistryv1beta1.Auth) error {
+	ecrSpec := auth.Spec.AWSElasticContainerRegistry
+	if ecrSpec.RoleARN == "" {
+		return fmt.Errorf("awsElasticContainerRegistry.roleArn is required")
+	}
+	if ecrSpec.Region == "" {
+		return fmt.Errorf("awsElasticContainerRegistry.region is required")
+	}
+	return nil
+}
+
+// DefaultAudiences returns the audience AWS STS expects for web identity tokens
+func (Provider) DefaultAudiences(auth *containerregistryv1beta1.Auth) []string {
+	return []string{STSAudience}
+}
+
+// sessionName identifies the Auth in CloudTrail, limited to 64 characters by STS
+func sessionName(auth *containerregistryv1beta1.Auth) string {
+	name := invalidSessionNameCharacters.ReplaceAllString(auth.Namespace+"."+auth.Name, "-")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+func (Provider) Exchange(ctx context.Context, request provider.Request) (*provider.Credentials, error) {
+	ecrSpec := request.Auth.Spec.AWSElasticContainerRegistry
+
+	stsEndpoint := ecrSpec.STSEndpoint
+	if stsEndpoint == "" {
+		stsEndpoint = STSEndpoint(ecrSpec.Region)
+	}
+	ecrEndpoint := ecrSpec.ECREndpoint
+	if ecrEndpoint == "" {
+		ecrEndpoint = ECREndpoint(ecrSpec.Region)
+	}
+
+	awsCredentials, err := AssumeRoleWithWebIdentity(ctx, stsEndpoint, ecrSpec.RoleARN, sessionName(request.Auth), request.SubjectToken)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Assume AWS Role: %w", err)
+	}
+
+	authorizationData, err := GetAuthorizationToken(ctx, ecrEndpoint, ecrSpec.Region, awsCredentials, ecrSpec.RegistryIDs)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Generate ECR Token: %w", err)
+	}
+
+	// ECR tokens are not scoped to a registry, any of them works for every registry the role can access,
+	// so the first one is used for all of the returned proxy endpoints
+	// https://docs.aws.amazon.com/AmazonECR/latest/APIReference/API_GetAuthorizationToken.html
+	userName, password, err := authorizationData[0].Credentials()
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := &provider.Credentials{
+		Username:   userName,
+		Password:   password,
+		Expiration: authorizationData[0].Expiration(),
+	}
+	for _, data := range authorizationData {
+		credentials.Registries = append(credentials.Registries, data.Registry())
+		if data.Expiration().Before(credentials.Expiration) {
+			credentials.Expiration = data.Expiration()
+		}
+	}
+
+	return credentials, nil
+}
+
+// SecretsManagerName is the Spec.ContainerRegistry value handled by SecretsManagerProvider
+const SecretsManagerName = "awsSecretsManager"
+
+// SecretsManagerProvider assumes a role with a Kubernetes token and reads registry credentials from Secrets Manager
+type SecretsManagerProvider struct{}
+
+func (SecretsManagerProvider) Validate(auth *containerregistryv1beta1.Auth) error {
+	secretsManagerSpec := auth.Spec.AWSSecretsManager
+	if secretsManagerSpec.RoleARN == "" {
+		return fmt.Errorf("awsSecretsManager.roleArn is required")
+	}
+	if secretsManagerSpec.Region == "" {
+		return fmt.Errorf("awsSecretsManager.region is required")
+	}
+	if secretsManagerSpec.SecretID == "" {
+		return fmt.Errorf("awsSecretsManager.secretId is required")
+	}
+	if len(secretsManagerSpec.Registries) == 0 {
+		return fmt.Errorf("awsSecretsManager.registries is required")
+	}
+	return nil
+}
+
+func (SecretsManagerProvider) DefaultAudiences(auth *containerregistryv1beta1.Auth) []string {
+	return []string{STSAudience}
+}
+
+func (SecretsManagerProvider) Exchange(ctx context.Context, request provider.Request) (*provider.Credentials, error) {
+	secretsManagerSpec := request.Auth.Spec.AWSSecretsManager
+
+	stsEndpoint := secretsManagerSpec.STSEndpoint
+	if stsEndpoint == "" {
+		stsEndpoint = STSEndpoint(secretsManagerSpec.Region)
+	}
+	secretsManagerEndpoint := secretsManagerSpec.SecretsManagerEndpoint
+	if secretsManagerEndpoint == "" {
+		secretsManagerEndpoint = SecretsManagerEndpoint(secretsManagerSpec.Region)
+	}
+
+	awsCredentials, err := AssumeRoleWithWebIdentity(ctx, stsEndpoint, secretsManagerSpec.RoleARN, sessionName(request.Auth), request.SubjectToken)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Assume AWS Role: %w", err)
+	}
+
+	secretString, err := GetSecretValue(ctx, secretsManagerEndpoint, secretsManagerSpec.Region, awsCredentials, secretsManagerSpec.SecretID, secretsManagerSpec.VersionStage)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Read AWS Secret: %w", err)
+	}
+
+	userName, password, err := provider.UsernamePassword([]byte(secretString), secretsManagerSpec.UsernameKey, secretsManagerSpec.PasswordKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Secrets have no expiry, re-read them when the assumed role credentials expire
+	return &provider.Credentials{
+		Username:   userName,
+		Password:   password,
+		Registries: secretsManagerSpec.Registries,
+		Expiration: awsCredentials.Expiration.UTC(),
+	}, nil
+}
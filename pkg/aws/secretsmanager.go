@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+)
+
+func SecretsManagerEndpoint(region string) string {
+	return "https://secretsmanager." + region + ".amazonaws.com"
+}
+
+// GetSecretValue returns the SecretString of a secret version
+func GetSecretValue(ctx context.Context, endpoint string, region string, credentials *Credentials, secretID string, versionStage string) (string, error) {
+	request := map[string]string{"SecretId": secretID}
+	if versionStage != "" {
+		request["VersionStage"] = versionStage
+	}
+
+	var result struct {
+		SecretString *string `json:"SecretString"`
+	}
+	err := callJSON(ctx, endpoint, region, "secretsmanager", "secretsmanager.GetSecretValue", credentials, request, &result)
+	if err != nil {
+		return "", err
+	}
+	if result.SecretString == nil {
+		return "", fmt.Errorf("secret '%s' has no SecretString", secretID)
+	}
+
+	return *result.SecretString, nil
+}
@@ -0,0 +1,65 @@
+package aws
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SigV4", func() {
+	exampleCredentials := &Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	sessionCredentials := &Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", SessionToken: "session"}
+	exampleTime := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	signedRequest := func(method string, url string, headers map[string][]string, credentials *Credentials) *http.Request {
+		req, err := http.NewRequest(method, url, nil)
+		Expect(err).NotTo(HaveOccurred())
+		for key, values := range headers {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+		signRequest(req, nil, credentials, "us-east-1", "service", exampleTime)
+		return req
+	}
+
+	// Signatures from the get-vanilla and post-vanilla cases of the AWS Signature Version 4 test suite
+	DescribeTable("Signing Requests",
+		func(method string, url string, authorization string) {
+			req := signedRequest(method, url, nil, exampleCredentials)
+			Expect(req.Header.Get("X-Amz-Date")).To(Equal("20150830T123600Z"))
+			Expect(req.Header.Get("Authorization")).To(Equal(authorization))
+		},
+		Entry("Get", "GET", "https://example.amazonaws.com/",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"),
+		Entry("Empty Path is Signed as /", "GET", "https://example.amazonaws.com",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"),
+		Entry("Post", "POST", "https://example.amazonaws.com/",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b"),
+	)
+
+	DescribeTable("Canonicalising Headers",
+		func(headers map[string][]string, equivalent map[string][]string, credentials *Credentials, signedHeaders string) {
+			authorization := signedRequest("POST", "https://example.amazonaws.com/", headers, credentials).Header.Get("Authorization")
+			Expect(authorization).To(ContainSubstring("SignedHeaders=" + signedHeaders + ","))
+			if equivalent != nil {
+				Expect(signedRequest("POST", "https://example.amazonaws.com/", equivalent, credentials).Header.Get("Authorization")).To(Equal(authorization))
+			}
+		},
+		Entry("Header Names are Lower Cased and Sorted",
+			map[string][]string{"X-Amz-Target": {"target"}, "Content-Type": {"application/x-amz-json-1.1"}},
+			map[string][]string{"x-amz-target": {"target"}, "content-type": {"application/x-amz-json-1.1"}},
+			exampleCredentials, "content-type;host;x-amz-date;x-amz-target"),
+		Entry("Header Values are Trimmed",
+			map[string][]string{"My-Header": {"  value  "}},
+			map[string][]string{"My-Header": {"value"}},
+			exampleCredentials, "host;my-header;x-amz-date"),
+		Entry("Repeated Headers are Joined with Commas",
+			map[string][]string{"My-Header": {"value1", "value2"}},
+			map[string][]string{"My-Header": {"value1,value2"}},
+			exampleCredentials, "host;my-header;x-amz-date"),
+		Entry("Session Tokens are Signed", nil, nil, sessionCredentials, "host;x-amz-date;x-amz-security-token"),
+	)
+})
@@ -0,0 +1,75 @@
+package aws
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+type Credentials struct {
+	AccessKeyID     string    `xml:"AccessKeyId"`
+	SecretAccessKey string    `xml:"SecretAccessKey"`
+	SessionToken    string    `xml:"SessionToken"`
+	Expiration      time.Time `xml:"Expiration"`
+}
+
+type assumeRoleWithWebIdentityResponse struct {
+	Result struct {
+		Credentials Credentials `xml:"Credentials"`
+	} `xml:"AssumeRoleWithWebIdentityResult"`
+}
+
+// STSAudience is the audience AWS IAM OIDC providers are configured with by default
+const STSAudience = "sts.amazonaws.com"
+
+func STSEndpoint(region string) string {
+	return "https://sts." + region + ".amazonaws.com"
+}
+
+// AssumeRoleWithWebIdentity exchanges an OIDC token for temporary AWS credentials.
+// The call is unsigned, the web identity token is the only credential.
+func AssumeRoleWithWebIdentity(ctx context.Context, endpoint string, roleARN string, sessionName string, webIdentityToken string) (*Credentials, error) {
+	form := url.Values{}
+	form.Set("Action", "AssumeRoleWithWebIdentity")
+	form.Set("Version", "2011-06-15")
+	form.Set("RoleArn", roleARN)
+	form.Set("RoleSessionName", sessionName)
+	form.Set("WebIdentityToken", webIdentityToken)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(endpoint, "/")+"/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := provider.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, provider.NewHTTPError(resp, body)
+	}
+
+	var result assumeRoleWithWebIdentityResponse
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal sts response. Error: %v", err)
+	}
+	if result.Result.Credentials.AccessKeyID == "" {
+		return nil, fmt.Errorf("sts response did not contain credentials")
+	}
+
+	return &result.Result.Credentials, nil
+}
@@ -0,0 +1,223 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+const (
+	// Username the Docker Credential Helper Convention Uses for ACR Refresh Tokens
+	RefreshTokenUserName           = "00000000-0000-0000-0000-000000000000"
+	DefaultResourceManagerEndpoint = "https://management.azure.com"
+	registryAPIVersion             = "2023-07-01"
+	// How long to wait for a long running operation, so one that never completes does not stall a reconcile
+	operationTimeout = 2 * time.Minute
+	// Delay between polls of a long running operation, when Azure does not give a Retry-After
+	operationPollInterval = 2 * time.Second
+)
+
+// GetRefreshToken trades an Entra access token for an ACR refresh token
+// https://github.com/Azure/acr/blob/main/docs/AAD-OAuth.md
+func GetRefreshToken(ctx context.Context, registryEndpoint string, registry string, tenantID string, accessToken string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "access_token")
+	form.Set("service", registry)
+	form.Set("tenant", tenantID)
+	form.Set("access_token", accessToken)
+
+	var result struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := postForm(ctx, strings.TrimSuffix(registryEndpoint, "/")+"/oauth2/exchange", form, &result); err != nil {
+		return "", err
+	}
+	if result.RefreshToken == "" {
+		return "", fmt.Errorf("acr response did not contain a refresh token")
+	}
+
+	return result.RefreshToken, nil
+}
+
+type ScopeMapTokenCredentials struct {
+	UserName   string
+	Password   string
+	Expiration time.Time
+}
+
+// GenerateScopeMapTokenCredentials issues a new expiring password1 for a registry token
+// https://learn.microsoft.com/en-us/rest/api/containerregistry/registries/generate-credentials
+func GenerateScopeMapTokenCredentials(ctx context.Context, resourceManagerEndpoint string, accessToken string, subscriptionID string, resourceGroup string, registryName string, tokenName string, expiration time.Time) (*ScopeMapTokenCredentials, error) {
+	registryID := "/subscriptions/" + subscriptionID + "/resourceGroups/" + resourceGroup + "/providers/Microsoft.ContainerRegistry/registries/" + registryName
+
+	payload, err := json.Marshal(map[string]string{
+		"tokenId": registryID + "/tokens/" + tokenName,
+		"expiry":  expiration.UTC().Format(time.RFC3339),
+		"name":    "password1",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(resourceManagerEndpoint, "/")+registryID+"/generateCredentials?api-version="+registryAPIVersion, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	resp, err := provider.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Azure may Generate the Credentials in a Long Running Operation
+	if resp.StatusCode == http.StatusAccepted {
+		if body, err = pollOperation(ctx, resp, accessToken); err != nil {
+			return nil, fmt.Errorf("unable to generate credentials: %w", err)
+		}
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, provider.NewHTTPError(resp, body)
+	}
+
+	var result struct {
+		Username  string `json:"username"`
+		Passwords []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"passwords"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal generate credentials response. Error: %v", err)
+	}
+	for _, password := range result.Passwords {
+		if password.Name == "password1" {
+			return &ScopeMapTokenCredentials{
+				UserName:   result.Username,
+				Password:   password.Value,
+				Expiration: expiration.UTC(),
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("generate credentials response did not contain password1")
+}
+
+// pollOperation waits for the long running operation accepted with resp to complete, and returns its result.
+// The Azure-AsyncOperation header is polled for the operation's status, then the Location header for its result.
+// https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/async-operations
+func pollOperation(ctx context.Context, resp *http.Response, accessToken string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
+	asyncOperation := resp.Header.Get("Azure-AsyncOperation")
+	location := resp.Header.Get("Location")
+	if asyncOperation == "" && location == "" {
+		return nil, fmt.Errorf("operation was accepted without an Azure-AsyncOperation or Location header to poll")
+	}
+
+	for asyncOperation != "" {
+		if err := waitToPoll(ctx, resp); err != nil {
+			return nil, err
+		}
+		var body []byte
+		var err error
+		if resp, body, err = get(ctx, asyncOperation, accessToken); err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, provider.NewHTTPError(resp, body)
+		}
+
+		var operation struct {
+			Status string `json:"status"`
+			Error  struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(body, &operation); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal operation status. Error: %v", err)
+		}
+		switch operation.Status {
+		case "Succeeded":
+			if location == "" {
+				return body, nil
+			}
+			asyncOperation = ""
+			resp = nil
+		case "Failed", "Canceled":
+			return nil, fmt.Errorf("operation %s: %s %s", strings.ToLower(operation.Status), operation.Error.Code, operation.Error.Message)
+		}
+	}
+
+	for {
+		if resp != nil {
+			if err := waitToPoll(ctx, resp); err != nil {
+				return nil, err
+			}
+		}
+		var body []byte
+		var err error
+		if resp, body, err = get(ctx, location, accessToken); err != nil {
+			return nil, err
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return body, nil
+		case http.StatusAccepted:
+			continue
+		default:
+			return nil, provider.NewHTTPError(resp, body)
+		}
+	}
+}
+
+// waitToPoll waits for the Retry-After of the operation's last response, or operationPollInterval.
+func waitToPoll(ctx context.Context, resp *http.Response) error {
+	delay := operationPollInterval
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		delay = time.Duration(seconds) * time.Second
+	}
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("operation did not complete: %w", ctx.Err())
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+func get(ctx context.Context, endpoint string, accessToken string) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	resp, err := provider.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp, body, nil
+}
@@ -0,0 +1,79 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+const (
+	DefaultAuthorityHost = "https://login.microsoftonline.com"
+	// Audience Entra expects on federated client assertions
+	TokenExchangeAudience = "api://AzureADTokenExchange"
+	RegistryScope         = "https://containerregistry.azure.net/.default"
+	ResourceManagerScope  = "https://management.azure.com/.default"
+)
+
+type AccessToken struct {
+	Token      string
+	Expiration time.Time
+}
+
+// postForm sends a form encoded request and decodes a json response
+func postForm(ctx context.Context, endpoint string, form url.Values, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := provider.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return provider.NewHTTPError(resp, body)
+	}
+
+	return json.Unmarshal(body, result)
+}
+
+// GetEntraToken exchanges a federated token as a client assertion for an Entra access token.
+func GetEntraToken(ctx context.Context, authorityHost string, tenantID string, clientID string, clientAssertion string, scope string) (*AccessToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", clientID)
+	form.Set("scope", scope)
+	form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+	form.Set("client_assertion", clientAssertion)
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	endpoint := strings.TrimSuffix(authorityHost, "/") + "/" + url.PathEscape(tenantID) + "/oauth2/v2.0/token"
+	if err := postForm(ctx, endpoint, form, &result); err != nil {
+		return nil, err
+	}
+	if result.AccessToken == "" {
+		return nil, fmt.Errorf("entra response did not contain an access token")
+	}
+
+	return &AccessToken{
+		Token:      result.AccessToken,
+		Expiration: time.Now().Add(time.Duration(result.ExpiresIn) * time.Second).UTC(),
+	}, nil
+}
@@ -0,0 +1,113 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/jwt"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// Name is the Spec.ContainerRegistry value handled by Provider
+const Name = "azureContainerRegistry"
+
+// Lifetime of Scope Map Token Passwords, Matching ACR Refresh Tokens
+const scopeMapTokenLifetime = 3 * time.Hour
+
+// Provider exchanges a Kubernetes token for ACR credentials via Entra workload identity federation
+type Provider struct{}
+
+func (Provider) Validate(auth *containerregistryv1beta1.Auth) error {
+	acrSpec := auth.Spec.AzureContainerRegistry
+	if acrSpec.Registry == "" {
+		return fmt.Errorf("azureContainerRegistry.registry is required")
+	}
+	if acrSpec.TenantID == "" || acrSpec.ClientID == "" {
+		return fmt.Errorf("azureContainerRegistry tenantId and clientId are required")
+	}
+	if acrSpec.ScopeMapToken != nil {
+		if acrSpec.ScopeMapToken.SubscriptionID == "" || acrSpec.ScopeMapToken.ResourceGroup == "" || acrSpec.ScopeMapToken.TokenName == "" {
+			return fmt.Errorf("azureContainerRegistry.scopeMapToken subscriptionId, resourceGroup and tokenName are required")
+		}
+	}
+	return nil
+}
+
+// DefaultAudiences returns the audience Entra ID expects for federated credentials
+func (Provider) DefaultAudiences(auth *containerregistryv1beta1.Auth) []string {
+	return []string{TokenExchangeAudience}
+}
+
+func (Provider) Exchange(ctx context.Context, request provider.Request) (*provider.Credentials, error) {
+	acrSpec := request.Auth.Spec.AzureContainerRegistry
+
+	authorityHost := acrSpec.AuthorityHost
+	if authorityHost == "" {
+		authorityHost = DefaultAuthorityHost
+	}
+
+	if acrSpec.ScopeMapToken != nil {
+		return scopeMapTokenCredentials(ctx, acrSpec, authorityHost, request.SubjectToken)
+	}
+
+	registryEndpoint := acrSpec.RegistryEndpoint
+	if registryEndpoint == "" {
+		registryEndpoint = "https://" + acrSpec.Registry
+	}
+
+	entraToken, err := GetEntraToken(ctx, authorityHost, acrSpec.TenantID, acrSpec.ClientID, request.SubjectToken, RegistryScope)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Generate Entra Token: %w", err)
+	}
+
+	refreshToken, err := GetRefreshToken(ctx, registryEndpoint, acrSpec.Registry, acrSpec.TenantID, entraToken.Token)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Generate ACR Refresh Token: %w", err)
+	}
+
+	expiration, err := jwt.Expiration(refreshToken)
+	if err != nil {
+		expiration = entraToken.Expiration
+	}
+
+	return &provider.Credentials{
+		Username:   RefreshTokenUserName,
+		Password:   refreshToken,
+		Registries: []string{acrSpec.Registry},
+		Expiration: expiration,
+	}, nil
+}
+
+func scopeMapTokenCredentials(ctx context.Context, acrSpec containerregistryv1beta1.AzureContainerRegistry, authorityHost string, subjectToken string) (*provider.Credentials, error) {
+	scopeMapToken := acrSpec.ScopeMapToken
+
+	resourceManagerEndpoint := scopeMapToken.ResourceManagerEndpoint
+	if resourceManagerEndpoint == "" {
+		resourceManagerEndpoint = DefaultResourceManagerEndpoint
+	}
+
+	entraToken, err := GetEntraToken(ctx, authorityHost, acrSpec.TenantID, acrSpec.ClientID, subjectToken, ResourceManagerScope)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Generate Entra Token: %w", err)
+	}
+
+	registryName, _, _ := strings.Cut(acrSpec.Registry, ".")
+	tokenCredentials, err := GenerateScopeMapTokenCredentials(ctx,
+		resourceManagerEndpoint, entraToken.Token,
+		scopeMapToken.SubscriptionID, scopeMapToken.ResourceGroup, registryName, scopeMapToken.TokenName,
+		time.Now().Add(scopeMapTokenLifetime),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Generate ACR Scope Map Token Credentials: %w", err)
+	}
+
+	return &provider.Credentials{
+		Username:   tokenCredentials.UserName,
+		Password:   tokenCredentials.Password,
+		Registries: []string{acrSpec.Registry},
+		Expiration: tokenCredentials.Expiration,
+	}, nil
+}
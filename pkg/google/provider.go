@@ -0,0 +1,144 @@
+package google
+
+import (
+	"context"
+	"fmt"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// Name is the Spec.ContainerRegistry value handled by Provider
+const Name = "googleArtifactRegistry"
+
+// Provider exchanges a Kubernetes token for a GCP access token via Workload Identity Federation
+type Provider struct{}
+
+func (Provider) Validate(auth *containerregistryv1beta1.Auth) error {
+	garSpec := auth.Spec.GoogleArtifactRegistry
+	if garSpec.RegistryLocation == "" {
+		return fmt.Errorf("googleArtifactRegistry.registryLocation is required")
+	}
+	if garSpec.Type == "inline" {
+		if garSpec.GoogleServiceAccount == "" || garSpec.GooglePoolProject == "" || garSpec.GooglePoolName == "" || garSpec.GoogleProviderName == "" {
+			return fmt.Errorf("googleArtifactRegistry googleServiceAccount, googlePoolProject, googlePoolName and googleProviderName are required for type inline")
+		}
+	} else {
+		if garSpec.ObjectName == "" || garSpec.FileName == "" {
+			return fmt.Errorf("googleArtifactRegistry objectName and fileName are required for type configMap")
+		}
+	}
+	return nil
+}
+
+// DefaultAudiences returns the default audience of the Workload Identity Pool provider, for type inline
+func (Provider) DefaultAudiences(auth *containerregistryv1beta1.Auth) []string {
+	garSpec := auth.Spec.GoogleArtifactRegistry
+	if garSpec.Type != "inline" {
+		return nil
+	}
+	return []string{PoolProviderAudience(garSpec.GooglePoolProject, garSpec.GooglePoolName, garSpec.GoogleProviderName)}
+}
+
+func (Provider) Exchange(ctx context.Context, request provider.Request) (*provider.Credentials, error) {
+	garSpec := request.Auth.Spec.GoogleArtifactRegistry
+
+	wifConfig := New(
+		request.Client, request.Auth.Namespace,
+		garSpec.ObjectName,
+		garSpec.FileName,
+		request.Auth.Spec.ServiceAccount,
+		garSpec.GoogleServiceAccount,
+		garSpec.GooglePoolProject,
+		garSpec.GooglePoolName,
+		garSpec.GoogleProviderName,
+		garSpec.Type,
+	)
+	wifToken, err := wifConfig.GetGcpWifToken(ctx, request.SubjectToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &provider.Credentials{
+		Username:   "oauth2accesstoken",
+		Password:   wifToken.AccessToken,
+		Registries: []string{garSpec.RegistryLocation + "-docker.pkg.dev"},
+		Expiration: wifToken.Expiry,
+	}, nil
+}
+
+// SecretManagerName is the Spec.ContainerRegistry value handled by SecretManagerProvider
+const SecretManagerName = "googleSecretManager"
+
+// SecretManagerProvider reads registry credentials from Secret Manager with a Workload Identity Federation token
+type SecretManagerProvider struct{}
+
+func (SecretManagerProvider) Validate(auth *containerregistryv1beta1.Auth) error {
+	secretManagerSpec := auth.Spec.GoogleSecretManager
+	if secretManagerSpec.SecretVersion == "" {
+		return fmt.Errorf("googleSecretManager.secretVersion is required")
+	}
+	if len(secretManagerSpec.Registries) == 0 {
+		return fmt.Errorf("googleSecretManager.registries is required")
+	}
+	if secretManagerSpec.Type == "inline" {
+		if secretManagerSpec.GoogleServiceAccount == "" || secretManagerSpec.GooglePoolProject == "" || secretManagerSpec.GooglePoolName == "" || secretManagerSpec.GoogleProviderName == "" {
+			return fmt.Errorf("googleSecretManager googleServiceAccount, googlePoolProject, googlePoolName and googleProviderName are required for type inline")
+		}
+	} else {
+		if secretManagerSpec.ObjectName == "" || secretManagerSpec.FileName == "" {
+			return fmt.Errorf("googleSecretManager objectName and fileName are required for type configMap")
+		}
+	}
+	return nil
+}
+
+func (SecretManagerProvider) DefaultAudiences(auth *containerregistryv1beta1.Auth) []string {
+	secretManagerSpec := auth.Spec.GoogleSecretManager
+	if secretManagerSpec.Type != "inline" {
+		return nil
+	}
+	return []string{PoolProviderAudience(secretManagerSpec.GooglePoolProject, secretManagerSpec.GooglePoolName, secretManagerSpec.GoogleProviderName)}
+}
+
+func (SecretManagerProvider) Exchange(ctx context.Context, request provider.Request) (*provider.Credentials, error) {
+	secretManagerSpec := request.Auth.Spec.GoogleSecretManager
+
+	wifConfig := New(
+		request.Client, request.Auth.Namespace,
+		secretManagerSpec.ObjectName,
+		secretManagerSpec.FileName,
+		request.Auth.Spec.ServiceAccount,
+		secretManagerSpec.GoogleServiceAccount,
+		secretManagerSpec.GooglePoolProject,
+		secretManagerSpec.GooglePoolName,
+		secretManagerSpec.GoogleProviderName,
+		secretManagerSpec.Type,
+	)
+	wifToken, err := wifConfig.GetGcpWifToken(ctx, request.SubjectToken)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := secretManagerSpec.Endpoint
+	if endpoint == "" {
+		endpoint = SecretManagerEndpoint
+	}
+	payload, err := AccessSecretVersion(ctx, endpoint, wifToken.AccessToken, secretManagerSpec.SecretVersion)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Read Google Secret: %w", err)
+	}
+
+	userName, password, err := provider.UsernamePassword(payload, secretManagerSpec.UsernameKey, secretManagerSpec.PasswordKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Secrets have no expiry, re-read them when the access token expires
+	return &provider.Credentials{
+		Username:   userName,
+		Password:   password,
+		Registries: secretManagerSpec.Registries,
+		Expiration: wifToken.Expiry,
+	}, nil
+}
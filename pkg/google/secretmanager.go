@@ -0,0 +1,53 @@
+package google
+
+import (
+	"context"
+	b64 "encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+const SecretManagerEndpoint = "https://secretmanager.googleapis.com"
+
+// AccessSecretVersion returns the payload of a secret version, for example projects/p/secrets/s/versions/latest
+func AccessSecretVersion(ctx context.Context, endpoint string, accessToken string, secretVersion string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimSuffix(endpoint, "/")+"/v1/"+strings.TrimPrefix(secretVersion, "/")+":access", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	resp, err := provider.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, provider.NewHTTPError(resp, body)
+	}
+
+	var result struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal secret manager response. Error: %v", err)
+	}
+
+	payload, err := b64.StdEncoding.DecodeString(result.Payload.Data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode secret payload. Error: %v", err)
+	}
+	return payload, nil
+}
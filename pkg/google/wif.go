@@ -4,34 +4,37 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path"
 	"strings"
 
 	"encoding/json"
 
 	coreV1 "k8s.io/api/core/v1"
 
+	"go.opentelemetry.io/otel"
 	"golang.org/x/oauth2"
 	auth "golang.org/x/oauth2/google"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/kubernetes"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/tracing"
 )
 
+var tracer = otel.Tracer("github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/google")
+
 type Wif struct {
 	client.Client
 	Namespace                      string
 	ConfigMapName                  string
 	ConfigMapKey                   string
 	ServiceAccount                 string
-	TokenExpirationSeconds         int
 	TokenDirectory                 string
 	RemoveTokenFile                bool
 	Audience                       string
 	ServiceAccountImpersonationUrl string
 	ConfigType                     string
-	TokenAudiences                 []string
+	// Path of the token file written by GetWifConfig
+	tokenPath string
 }
 
 func New(
@@ -45,7 +48,6 @@ func New(
 	googlePoolName string,
 	googleProviderName string,
 	configType string,
-	tokenAudiences []string,
 ) Wif {
 	return Wif{
 		Client:                         client,
@@ -56,13 +58,16 @@ func New(
 		ConfigType:                     configType,
 		Audience:                       "//iam.googleapis.com/projects/" + googlePoolProject + "/locations/global/workloadIdentityPools/" + googlePoolName + "/providers/" + googleProviderName,
 		ServiceAccountImpersonationUrl: "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/" + googleServiceAccount + ":generateAccessToken",
-		TokenAudiences:                 tokenAudiences,
-		TokenExpirationSeconds:         3600,
 		TokenDirectory:                 "/tmp/tokens/",
 		RemoveTokenFile:                true,
 	}
 }
 
+// PoolProviderAudience is the default audience of a Workload Identity Pool provider
+func PoolProviderAudience(googlePoolProject string, googlePoolName string, googleProviderName string) string {
+	return "https://iam.googleapis.com/projects/" + googlePoolProject + "/locations/global/workloadIdentityPools/" + googlePoolName + "/providers/" + googleProviderName
+}
+
 type WifConfigJson struct {
 	Type                           string `json:"type"`
 	Audience                       string `json:"audience"`
@@ -77,7 +82,11 @@ type WifConfigJson struct {
 	} `json:"credential_source"`
 }
 
-func gcpAccessToken(ctx context.Context, wifConfig []byte) (*oauth2.Token, error) {
+func gcpAccessToken(ctx context.Context, wifConfig []byte) (_ *oauth2.Token, err error) {
+	// Covers the STS Exchange and Service Account Impersonation
+	ctx, span := tracer.Start(ctx, "gcpAccessToken")
+	defer func() { tracing.End(span, err) }()
+
 	// https://stackoverflow.com/questions/72275338/get-access-token-for-a-google-cloud-service-account-in-golang
 	var token *oauth2.Token
 
@@ -96,15 +105,15 @@ func gcpAccessToken(ctx context.Context, wifConfig []byte) (*oauth2.Token, error
 	return token, nil
 }
 
-func (r *Wif) GetGcpWifTokenWithTokenSource(ctx context.Context) (*RawTokenSource, error) {
-	token, err := r.GetGcpWifToken(ctx)
+func (r *Wif) GetGcpWifTokenWithTokenSource(ctx context.Context, kubernetesToken string) (*RawTokenSource, error) {
+	token, err := r.GetGcpWifToken(ctx, kubernetesToken)
 	if err != nil {
 		return nil, err
 	}
 	return &RawTokenSource{RawToken: token}, nil
 }
 
-func (r *Wif) GetWifConfig(ctx context.Context) ([]byte, error) {
+func (r *Wif) GetWifConfig(ctx context.Context, kubernetesToken string) ([]byte, error) {
 	var wifConfig string
 	var keyFound bool
 
@@ -133,43 +142,46 @@ func (r *Wif) GetWifConfig(ctx context.Context) ([]byte, error) {
 		var gcpCredentials coreV1.ConfigMap
 		err := r.Get(ctx, client.ObjectKey{Name: r.ConfigMapName, Namespace: r.Namespace}, &gcpCredentials)
 		if err != nil {
-			return nil, fmt.Errorf("configMap '%s' not found. Error: %v", r.ConfigMapName, err)
+			return nil, &provider.ConfigMapError{Name: r.ConfigMapName, Err: fmt.Errorf("configMap '%s' not found. Error: %w", r.ConfigMapName, err)}
 		}
 
 		// Get Wif Config
 		wifConfig, keyFound = gcpCredentials.Data[r.ConfigMapKey]
 		if !keyFound {
 
-			return nil, fmt.Errorf("configMap key '%s' not found. Error: %v", r.ConfigMapKey, err)
+			return nil, &provider.ConfigMapError{Name: r.ConfigMapName, Err: fmt.Errorf("configMap key '%s' not found", r.ConfigMapKey)}
 		}
 
 		// Generate GCP wif config
 		err = json.Unmarshal([]byte(wifConfig), &WifConfigJSON)
 		if err != nil {
-			return nil, fmt.Errorf("unable to unmarshal wif config object. Error: %v", err)
+			return nil, &provider.ConfigMapError{Name: r.ConfigMapName, Err: fmt.Errorf("unable to unmarshal wif config object. Error: %w", err)}
 		}
 	}
 
-	// Generate k8s Auth Token
-	kubernetesAuth := kubernetes.New(r.Client)
-	kubernetesToken, err := kubernetesAuth.GetKubernetesAuthToken(ctx, r.ServiceAccount, r.Namespace, r.TokenExpirationSeconds, r.TokenAudiences)
-
 	// Save Token to FileSystem
 
-	tokenPath := r.GetTokenPath()
-	err = os.Mkdir(r.TokenDirectory, 0755)
+	err := os.Mkdir(r.TokenDirectory, 0755)
 	if err != nil {
 		if !strings.Contains(err.Error(), "file exists") {
 			return nil, fmt.Errorf("unable to create token directory '%s'. Error: %v", r.TokenDirectory, err)
 		}
 	}
-	d1 := []byte(kubernetesToken.Status.Token)
-	err = os.WriteFile(tokenPath, d1, 0644) // Can this be done without using the filesystem?
+	// Each exchange gets its own file, concurrent reconciles for the same service account can request different audiences
+	tokenFile, err := os.CreateTemp(r.TokenDirectory, r.Namespace+"-"+r.ServiceAccount+"-*")
 	if err != nil {
-		return nil, fmt.Errorf("unable to write token file '%s'. Error: %v", tokenPath, err)
+		return nil, fmt.Errorf("unable to create token file in '%s'. Error: %v", r.TokenDirectory, err)
+	}
+	r.tokenPath = tokenFile.Name()
+	_, err = tokenFile.WriteString(kubernetesToken) // Can this be done without using the filesystem?
+	if closeErr := tokenFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to write token file '%s'. Error: %v", r.tokenPath, err)
 	}
 
-	WifConfigJSON.CredentialSource.File = tokenPath
+	WifConfigJSON.CredentialSource.File = r.tokenPath
 	WifConfigByte, err := json.Marshal(WifConfigJSON)
 	if err != nil {
 		return nil, fmt.Errorf("unable to marshal wif object. Error: %v", err)
@@ -177,30 +189,33 @@ func (r *Wif) GetWifConfig(ctx context.Context) ([]byte, error) {
 	return WifConfigByte, nil
 }
 
-func (r *Wif) GetGcpWifToken(ctx context.Context) (*oauth2.Token, error) {
-	WifConfigByte, err := r.GetWifConfig(ctx)
+func (r *Wif) GetGcpWifToken(ctx context.Context, kubernetesToken string) (*oauth2.Token, error) {
+	if r.RemoveTokenFile {
+		defer r.RemoveToken()
+	}
+	WifConfigByte, err := r.GetWifConfig(ctx, kubernetesToken)
 	if err != nil {
 		return nil, err
 	}
 	token, err := gcpAccessToken(ctx, WifConfigByte)
 	if err != nil {
 
-		return nil, fmt.Errorf("unable to create google access token. Error: %v", err)
+		return nil, fmt.Errorf("unable to create google access token. Error: %w", err)
 	}
 
-	if r.RemoveTokenFile {
-		r.RemoveToken()
-	}
 	return token, nil
 }
 
 func (r *Wif) RemoveToken() error {
-	return os.Remove(r.GetTokenPath())
+	if r.tokenPath == "" {
+		return nil
+	}
+	return os.Remove(r.tokenPath)
 }
 
+// GetTokenPath returns the path of the token file written by the last GetWifConfig
 func (r *Wif) GetTokenPath() string {
-	tokenName := r.Namespace + "-" + r.ServiceAccount
-	return path.Join(r.TokenDirectory, tokenName)
+	return r.tokenPath
 }
 
 type RawTokenSource struct {
@@ -0,0 +1,129 @@
+package harbor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// https://goharbor.io/docs/main/working-with-projects/project-configuration/create-robot-accounts/
+
+type Client struct {
+	URL      string
+	Username string
+	Password string
+}
+
+type Robot struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Secret    string `json:"secret,omitempty"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// BaseURL adds https:// to URLs without a scheme
+func BaseURL(url string) string {
+	if !strings.Contains(url, "://") {
+		url = "https://" + url
+	}
+	return strings.TrimSuffix(url, "/")
+}
+
+// Host of the URL, without the scheme or path
+func Host(url string) string {
+	host := BaseURL(url)
+	_, host, _ = strings.Cut(host, "://")
+	host, _, _ = strings.Cut(host, "/")
+	return host
+}
+
+func (r *Client) do(ctx context.Context, method string, path string, payload interface{}, result interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(payloadBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, BaseURL(r.URL)+"/api/v2.0"+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(r.Username, r.Password)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := provider.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return provider.NewHTTPError(resp, respBody)
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(respBody, result); err != nil {
+			return fmt.Errorf("unable to unmarshal harbor response. Error: %v", err)
+		}
+	}
+	return nil
+}
+
+// CreateProjectRobot creates a robot account with repository access to a single project
+func (r *Client) CreateProjectRobot(ctx context.Context, project string, name string, durationDays int, actions []string) (*Robot, error) {
+	access := make([]map[string]string, 0, len(actions))
+	for _, action := range actions {
+		access = append(access, map[string]string{"resource": "repository", "action": action})
+	}
+
+	var robot Robot
+	err := r.do(ctx, "POST", "/robots", map[string]interface{}{
+		"name":        name,
+		"description": "Managed by container-registry-k8s-auth-controller",
+		"duration":    durationDays,
+		"level":       "project",
+		"permissions": []map[string]interface{}{{
+			"kind":      "project",
+			"namespace": project,
+			"access":    access,
+		}},
+	}, &robot)
+	if err != nil {
+		return nil, err
+	}
+	return &robot, nil
+}
+
+// ListRobots returns robot accounts whose name contains the given string
+func (r *Client) ListRobots(ctx context.Context, name string) ([]Robot, error) {
+	var robots []Robot
+	err := r.do(ctx, "GET", "/robots?page_size=100&q="+url.QueryEscape("name=~"+name), nil, &robots)
+	if err != nil {
+		return nil, err
+	}
+	return robots, nil
+}
+
+func (r *Client) DeleteRobot(ctx context.Context, id int64) error {
+	return r.do(ctx, "DELETE", "/robots/"+strconv.FormatInt(id, 10), nil, nil)
+}
+
+func (r *Robot) Expiration() time.Time {
+	return time.Unix(r.ExpiresAt, 0).UTC()
+}
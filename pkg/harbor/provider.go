@@ -0,0 +1,126 @@
+package harbor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// Name is the Spec.ContainerRegistry value handled by Provider
+const Name = "harbor"
+
+// Provider rotates short lived Harbor robot accounts using an admin credential.
+// Harbor does not federate, so the Kubernetes token is not used.
+type Provider struct{}
+
+func (Provider) Validate(auth *containerregistryv1beta1.Auth) error {
+	harborSpec := auth.Spec.Harbor
+	if harborSpec.URL == "" {
+		return fmt.Errorf("harbor.url is required")
+	}
+	if harborSpec.Project == "" {
+		return fmt.Errorf("harbor.project is required")
+	}
+	if harborSpec.AdminSecretName == "" {
+		return fmt.Errorf("harbor.adminSecretName is required")
+	}
+	return nil
+}
+
+// Tokenless as Harbor does not federate.
+func (Provider) Tokenless() {}
+
+func defaultString(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func (Provider) Exchange(ctx context.Context, request provider.Request) (*provider.Credentials, error) {
+	harborSpec := request.Auth.Spec.Harbor
+
+	var adminSecret coreV1.Secret
+	err := request.Client.Get(ctx, client.ObjectKey{Name: harborSpec.AdminSecretName, Namespace: request.Auth.Namespace}, &adminSecret)
+	if err != nil {
+		return nil, fmt.Errorf("secret '%s' not found. Error: %w", harborSpec.AdminSecretName, err)
+	}
+	userNameKey := defaultString(harborSpec.UsernameKey, "username")
+	passwordKey := defaultString(harborSpec.PasswordKey, "password")
+	userName, keyFound := adminSecret.Data[userNameKey]
+	if !keyFound {
+		return nil, fmt.Errorf("secret key '%s' not found", userNameKey)
+	}
+	password, keyFound := adminSecret.Data[passwordKey]
+	if !keyFound {
+		return nil, fmt.Errorf("secret key '%s' not found", passwordKey)
+	}
+
+	harborClient := Client{
+		URL:      harborSpec.URL,
+		Username: string(userName),
+		Password: string(password),
+	}
+
+	durationDays := harborSpec.DurationDays
+	if durationDays < 1 {
+		durationDays = 1
+	}
+	actions := harborSpec.Actions
+	if len(actions) == 0 {
+		actions = []string{"pull"}
+	}
+
+	robotPrefix := defaultString(harborSpec.RobotPrefix, request.Auth.Namespace+"-"+request.Auth.Name)
+	robot, err := harborClient.CreateProjectRobot(ctx, harborSpec.Project, robotPrefix+"-"+strconv.FormatInt(time.Now().Unix(), 10), durationDays, actions)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Create Harbor Robot Account: %w", err)
+	}
+
+	// The new robot exists, so its credentials are returned even when the cleanup fails,
+	// the superseded robots are deleted on the next rotation.
+	if err := deleteSupersededRobots(ctx, &harborClient, harborSpec.Project, robotPrefix, robot.ID); err != nil {
+		log.FromContext(ctx).Error(err, "Unable to Delete Superseded Harbor Robot Accounts", "project", harborSpec.Project, "prefix", robotPrefix)
+	}
+
+	return &provider.Credentials{
+		Username:   robot.Name,
+		Password:   robot.Secret,
+		Registries: []string{Host(harborSpec.URL)},
+		Expiration: robot.Expiration(),
+	}, nil
+}
+
+// deleteSupersededRobots removes all but the newest previous robot account,
+// which is kept so pulls using the previous secret keep working until it is replaced.
+func deleteSupersededRobots(ctx context.Context, harborClient *Client, project string, robotPrefix string, currentID int64) error {
+	robots, err := harborClient.ListRobots(ctx, robotPrefix+"-")
+	if err != nil {
+		return err
+	}
+
+	var previous []Robot
+	for _, robot := range robots {
+		if robot.ID != currentID && strings.Contains(robot.Name, project+"+"+robotPrefix+"-") {
+			previous = append(previous, robot)
+		}
+	}
+	sort.Slice(previous, func(i, j int) bool { return previous[i].ID > previous[j].ID })
+
+	for i := 1; i < len(previous); i++ {
+		if err := harborClient.DeleteRobot(ctx, previous[i].ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -30,23 +30,35 @@ func getClaim(tokenString string) (map[string]interface{}, error) {
 	}
 }
 
-func TokenExpiration(tokenString string) (string, error) {
+func Claims(tokenString string) (map[string]interface{}, error) {
+	return getClaim(tokenString)
+}
+
+func Expiration(tokenString string) (time.Time, error) {
 
 	claims, err := getClaim(tokenString)
 	if err != nil {
-		return "", fmt.Errorf("Error getting claims: %v", err)
+		return time.Time{}, fmt.Errorf("Error getting claims: %v", err)
 	}
 
 	exp, ok := claims["exp"].(float64)
 	if ok {
-		expirationTime := time.Unix(int64(exp), 0).UTC()
-		return expirationTime.String(), nil
+		return time.Unix(int64(exp), 0).UTC(), nil
 	} else {
-		return "", fmt.Errorf("exp claim not found or wrong type")
+		return time.Time{}, fmt.Errorf("exp claim not found or wrong type")
 
 	}
 }
 
+func TokenExpiration(tokenString string) (string, error) {
+
+	expirationTime, err := Expiration(tokenString)
+	if err != nil {
+		return "", err
+	}
+	return expirationTime.String(), nil
+}
+
 func Issuer(tokenString string) (string, error) {
 
 	claims, err := getClaim(tokenString)
@@ -4,11 +4,17 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	authenticationV1 "k8s.io/api/authentication/v1"
 	coreV1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/tracing"
 )
 
+var tracer = otel.Tracer("github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/kubernetes")
+
 type Auth struct {
 	client.Client
 	Namespace               string
@@ -38,19 +44,26 @@ func authToken(expirationSeconds int, audiences []string) *authenticationV1.Toke
 	return tokenRequest
 }
 
-func (r *Auth) GetKubernetesAuthToken(ctx context.Context, federatedServiceAccount string, namespace string, tokenExpirationSeconds int, audiences []string) (*authenticationV1.TokenRequest, error) {
+func (r *Auth) GetKubernetesAuthToken(ctx context.Context, federatedServiceAccount string, namespace string, tokenExpirationSeconds int, audiences []string) (_ *authenticationV1.TokenRequest, err error) {
+	ctx, span := tracer.Start(ctx, "GetKubernetesAuthToken")
+	span.SetAttributes(
+		attribute.String("k8s.namespace.name", namespace),
+		attribute.String("k8s.serviceaccount.name", federatedServiceAccount),
+		attribute.StringSlice("audiences", audiences),
+	)
+	defer func() { tracing.End(span, err) }()
 
 	// Generate k8s Auth Token
 	var serviceAccount coreV1.ServiceAccount
 	k8sAuthToken := authToken(tokenExpirationSeconds, audiences)
-	err := r.Get(ctx, client.ObjectKey{Name: federatedServiceAccount, Namespace: namespace}, &serviceAccount)
+	err = r.Get(ctx, client.ObjectKey{Name: federatedServiceAccount, Namespace: namespace}, &serviceAccount)
 	if err != nil {
 
-		return nil, fmt.Errorf("service Account '%s' Not Found. Error: %v", federatedServiceAccount, err)
+		return nil, fmt.Errorf("service Account '%s' Not Found. Error: %w", federatedServiceAccount, err)
 	}
 	err = r.SubResource("token").Create(ctx, &serviceAccount, k8sAuthToken)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create kubernetes token. Error: %v", err)
+		return nil, fmt.Errorf("unable to create kubernetes token. Error: %w", err)
 	}
 
 	return k8sAuthToken, nil
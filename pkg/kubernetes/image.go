@@ -0,0 +1,57 @@
+package kubernetes
+
+import (
+	"strings"
+)
+
+// ImageRegistry Returns the Registry Host of an Image Reference, docker.io when the Image has None
+func ImageRegistry(image string) string {
+	host, _, found := strings.Cut(image, "/")
+	if !found || (!strings.ContainsAny(host, ".:") && host != "localhost") {
+		return "docker.io"
+	}
+	if host == "index.docker.io" {
+		return "docker.io"
+	}
+	return host
+}
+
+// imageRepository Returns the Repository Path of an Image Reference, without the Host, Tag or Digest.
+// Docker Hub's Official Images are in the library Repository.
+func imageRepository(image string) string {
+	repository, _, _ := strings.Cut(image, "@")
+	if host, path, found := strings.Cut(repository, "/"); found && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		repository = path
+	}
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+	if ImageRegistry(image) == "docker.io" && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	return repository
+}
+
+// RegistryMatchesImage Reports Whether a .dockerconfigjson Registry Key Applies to the Image.
+// Like the Kubelet, Keys may be Prefixed with a Scheme, Contain a Path Prefix, or a Leading Wildcard Subdomain.
+func RegistryMatchesImage(registry string, image string) bool {
+	registry = strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://")
+	registryHost, registryPath, _ := strings.Cut(strings.TrimSuffix(registry, "/"), "/")
+	if registryHost == "index.docker.io" {
+		// https://index.docker.io/v1/
+		registryHost = "docker.io"
+		registryPath = ""
+	}
+
+	imageHost := ImageRegistry(image)
+	if wildcard, found := strings.CutPrefix(registryHost, "*."); found {
+		if !strings.HasSuffix(imageHost, "."+wildcard) {
+			return false
+		}
+	} else if registryHost != imageHost {
+		return false
+	}
+
+	repository := imageRepository(image)
+	return registryPath == "" || repository == registryPath || strings.HasPrefix(repository, registryPath+"/")
+}
@@ -1,8 +1,14 @@
 package kubernetes
 
 import (
+	"crypto/sha256"
 	b64 "encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -18,13 +24,134 @@ func ImagePullSecretConfig(userName string, token string, url string) string {
 	return ImagePullSecret
 }
 
-func ImagePullSecretObject(name string, namespace string, dockerConfig string, ownerReference []metaV1.OwnerReference) *coreV1.Secret {
+func ImagePullSecretConfigs(userName string, token string, urls []string) string {
+	auths := ImagePullSecretAuths{}
+	auths.Add(userName, token, urls)
+
+	return auths.String()
+}
+
+// ImagePullSecretAuths Merges the Credentials of Several Registries into One .dockerconfigjson
+type ImagePullSecretAuths map[string]map[string]string
+
+func (auths ImagePullSecretAuths) Add(userName string, token string, urls []string) {
+	BASE64TOKEN := b64.StdEncoding.EncodeToString([]byte(userName + ":" + token))
+	for _, url := range urls {
+		auths[url] = map[string]string{"auth": BASE64TOKEN}
+	}
+}
+
+// Registries Returns the Registry Hosts in Sorted Order
+func (auths ImagePullSecretAuths) Registries() []string {
+	registries := make([]string, 0, len(auths))
+	for registry := range auths {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+
+	return registries
+}
+
+// ParseImagePullSecretAuths reads the credentials of each registry in a .dockerconfigjson.
+func ParseImagePullSecretAuths(dockerConfig []byte) (ImagePullSecretAuths, error) {
+	var config struct {
+		Auths ImagePullSecretAuths `json:"auths"`
+	}
+	if err := json.Unmarshal(dockerConfig, &config); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal .dockerconfigjson. Error: %v", err)
+	}
+
+	return config.Auths, nil
+}
+
+func (auths ImagePullSecretAuths) String() string {
+	ImagePullSecret, _ := json.Marshal(map[string]interface{}{"auths": auths})
+
+	return string(ImagePullSecret)
+}
+
+// Annotations recording how the credentials in an image pull secret were issued.
+const (
+	IssuedAtAnnotation = "containerregistry.arthurvardevanyan.com/issued-at"
+	ExpiryAnnotation   = "containerregistry.arthurvardevanyan.com/expiry"
+	ProviderAnnotation = "containerregistry.arthurvardevanyan.com/provider"
+	SpecHashAnnotation = "containerregistry.arthurvardevanyan.com/spec-hash"
+	DataHashAnnotation = "containerregistry.arthurvardevanyan.com/data-hash"
+)
+
+// ManagedLabel is set to "true" on every image pull secret the controller writes, so its cache can be limited to them.
+const ManagedLabel = "containerregistry.arthurvardevanyan.com/managed"
+
+// PullSecretMetadata describes the credentials in an image pull secret.
+type PullSecretMetadata struct {
+	IssuedAt time.Time
+	// Zero when the credentials do not expire
+	Expiration time.Time
+	// The container registries the credentials were issued by, comma separated
+	Provider string
+	// Hash of the spec the credentials were issued for
+	SpecHash string
+	// Hash of the .dockerconfigjson written, to detect edits to the secret
+	DataHash string
+}
+
+// DockerConfigHash returns the hash of a .dockerconfigjson recorded in the DataHashAnnotation.
+func DockerConfigHash(dockerConfig []byte) string {
+	hash := sha256.Sum256(dockerConfig)
+
+	return hex.EncodeToString(hash[:])
+}
+
+func (m PullSecretMetadata) Annotations() map[string]string {
+	annotations := map[string]string{
+		IssuedAtAnnotation: m.IssuedAt.UTC().Format(time.RFC3339),
+		ProviderAnnotation: m.Provider,
+		SpecHashAnnotation: m.SpecHash,
+		DataHashAnnotation: m.DataHash,
+	}
+	if !m.Expiration.IsZero() {
+		annotations[ExpiryAnnotation] = m.Expiration.UTC().Format(time.RFC3339)
+	}
+
+	return annotations
+}
+
+// ParsePullSecretMetadata reads the metadata annotations of an image pull secret.
+func ParsePullSecretMetadata(secret *coreV1.Secret) (PullSecretMetadata, error) {
+	metadata := PullSecretMetadata{
+		Provider: secret.Annotations[ProviderAnnotation],
+		SpecHash: secret.Annotations[SpecHashAnnotation],
+		DataHash: secret.Annotations[DataHashAnnotation],
+	}
+	if metadata.SpecHash == "" {
+		return metadata, fmt.Errorf("secret '%s' has no %s annotation", secret.Name, SpecHashAnnotation)
+	}
+
+	issuedAt, err := time.Parse(time.RFC3339, secret.Annotations[IssuedAtAnnotation])
+	if err != nil {
+		return metadata, fmt.Errorf("secret '%s' has an invalid %s annotation: %w", secret.Name, IssuedAtAnnotation, err)
+	}
+	metadata.IssuedAt = issuedAt
+
+	if expiry, ok := secret.Annotations[ExpiryAnnotation]; ok {
+		if metadata.Expiration, err = time.Parse(time.RFC3339, expiry); err != nil {
+			return metadata, fmt.Errorf("secret '%s' has an invalid %s annotation: %w", secret.Name, ExpiryAnnotation, err)
+		}
+	}
+
+	return metadata, nil
+}
+
+func ImagePullSecretObject(name string, namespace string, dockerConfig string, ownerReference []metaV1.OwnerReference, metadata PullSecretMetadata) *coreV1.Secret {
+	metadata.DataHash = DockerConfigHash([]byte(dockerConfig))
 	// https://stackoverflow.com/questions/64758486/how-to-create-docker-secret-with-client-go
 	secret := &coreV1.Secret{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:            name,
 			Namespace:       namespace,
 			OwnerReferences: ownerReference,
+			Labels:          map[string]string{ManagedLabel: "true"},
+			Annotations:     metadata.Annotations(),
 		},
 		Type:       "kubernetes.io/dockerconfigjson",
 		StringData: map[string]string{".dockerconfigjson": dockerConfig},
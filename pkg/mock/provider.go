@@ -0,0 +1,73 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/jwt"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// Name is the Spec.ContainerRegistry value handled by Provider
+const Name = "mock"
+
+const (
+	UserName        = "mock"
+	DefaultRegistry = "registry.local"
+	DefaultLifetime = time.Hour
+)
+
+// Provider signs its own tokens, for development clusters and tests without registry access
+type Provider struct {
+	Signer *Signer
+}
+
+func (r Provider) Validate(auth *containerregistryv1beta1.Auth) error {
+	if r.Signer == nil {
+		return fmt.Errorf("mock provider has no signer")
+	}
+	if auth.Spec.Mock.TokenLifetime.Duration < 0 {
+		return fmt.Errorf("mock.tokenLifetime must be positive")
+	}
+	return nil
+}
+
+// KeyID of the Signer, as its key is generated at startup
+func (r Provider) KeyID() string {
+	if r.Signer == nil {
+		return ""
+	}
+	return r.Signer.KeyID()
+}
+
+func (r Provider) Exchange(ctx context.Context, request provider.Request) (*provider.Credentials, error) {
+	mockSpec := request.Auth.Spec.Mock
+
+	registry := mockSpec.Registry
+	if registry == "" {
+		registry = DefaultRegistry
+	}
+	lifetime := mockSpec.TokenLifetime.Duration
+	if lifetime == 0 {
+		lifetime = DefaultLifetime
+	}
+
+	subject, err := jwt.Subject(request.SubjectToken)
+	if err != nil {
+		return nil, err
+	}
+
+	token, expiration, err := r.Signer.Sign(subject, registry, lifetime)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Sign Mock Token: %w", err)
+	}
+
+	return &provider.Credentials{
+		Username:   UserName,
+		Password:   token,
+		Registries: []string{registry},
+		Expiration: expiration,
+	}, nil
+}
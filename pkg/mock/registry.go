@@ -0,0 +1,68 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RegistryHandler serves the authentication part of the registry API.
+// Every /v2/ request succeeds with a valid token as the basic auth password and is challenged otherwise,
+// which is enough for a client to verify a pull secret without a real registry.
+// The registry host the tokens must be issued for is the request's Host.
+func (r *Signer) RegistryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.URL.Path, "/v2/") {
+			http.NotFound(w, req)
+			return
+		}
+
+		w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+		_, token, ok := req.BasicAuth()
+		if !ok {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", req.Host))
+			http.Error(w, `{"errors":[{"code":"UNAUTHORIZED","message":"authentication required"}]}`, http.StatusUnauthorized)
+			return
+		}
+		if _, err := r.Verify(token, req.Host); err != nil {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", req.Host))
+			http.Error(w, fmt.Sprintf(`{"errors":[{"code":"UNAUTHORIZED","message":%q}]}`, err.Error()), http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "{}")
+	})
+}
+
+// RegistryServer runs the RegistryHandler as a manager Runnable
+type RegistryServer struct {
+	Addr   string
+	Signer *Signer
+}
+
+func NewRegistryServer(addr string, signer *Signer) *RegistryServer {
+	return &RegistryServer{
+		Addr:   addr,
+		Signer: signer,
+	}
+}
+
+// Start serves until the context is cancelled
+func (r *RegistryServer) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              r.Addr,
+		Handler:           r.Signer.RegistryHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package mock
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const Issuer = "container-registry-k8s-auth-controller/mock"
+
+// Signer issues and verifies ES256 tokens with a key generated at startup.
+// Tokens do not survive a controller restart.
+type Signer struct {
+	key *ecdsa.PrivateKey
+}
+
+type Claims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+func NewSigner() (*Signer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate mock signing key. Error: %v", err)
+	}
+	return &Signer{key: key}, nil
+}
+
+// KeyID identifies the signing key by a hash of its public key
+func (r *Signer) KeyID() string {
+	publicKey, err := r.key.PublicKey.ECDH()
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(publicKey.Bytes())
+	return hex.EncodeToString(hash[:8])
+}
+
+func encodeSegment(value interface{}) (string, error) {
+	segment, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(segment), nil
+}
+
+// Sign issues a token for the subject, valid for the registry until the lifetime elapses
+func (r *Signer) Sign(subject string, registry string, lifetime time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiration := now.Add(lifetime).Truncate(time.Second).UTC()
+
+	header, err := encodeSegment(map[string]string{"alg": "ES256", "typ": "JWT"})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	payload, err := encodeSegment(Claims{
+		Issuer:    Issuer,
+		Subject:   subject,
+		Audience:  registry,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiration.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	signingInput := header + "." + payload
+	digest := sha256.Sum256([]byte(signingInput))
+	sigR, sigS, err := ecdsa.Sign(rand.Reader, r.key, digest[:])
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	signature := make([]byte, 64)
+	sigR.FillBytes(signature[:32])
+	sigS.FillBytes(signature[32:])
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), expiration, nil
+}
+
+// Verify checks the signature, expiry and audience of a token
+func (r *Signer) Verify(token string, registry string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid token format")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(signature) != 64 {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !ecdsa.Verify(&r.key.PublicKey, digest[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])) {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token payload")
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("invalid token payload")
+	}
+	if claims.Issuer != Issuer {
+		return nil, fmt.Errorf("invalid token issuer")
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, fmt.Errorf("token expired")
+	}
+	if registry != "" && claims.Audience != registry {
+		return nil, fmt.Errorf("token is not valid for registry '%s'", registry)
+	}
+	return &claims, nil
+}
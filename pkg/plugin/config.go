@@ -0,0 +1,74 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the controller level plugin configuration, only plugins listed here can be run
+//
+//	plugins:
+//	  - name: example
+//	    path: /plugins/example
+//	    args: ["--verbose"]
+//	    env: ["HTTPS_PROXY=http://proxy:3128"]
+//	    timeout: 30s
+type Config struct {
+	Plugins []PluginConfig `json:"plugins"`
+}
+
+type PluginConfig struct {
+	// Name Auths Reference the Plugin by
+	Name string `json:"name"`
+	// Absolute Path to the Plugin Binary
+	Path string   `json:"path"`
+	Args []string `json:"args,omitempty"`
+	// Environment of the Plugin in KEY=VALUE Form, the Controller's Environment is not Inherited
+	Env []string `json:"env,omitempty"`
+	// How Long the Plugin May Run, defaults to 30s
+	Timeout string `json:"timeout,omitempty"`
+
+	timeout time.Duration
+}
+
+const defaultTimeout = 30 * time.Second
+
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read plugin config '%s'. Error: %v", path, err)
+	}
+
+	var config Config
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal plugin config '%s'. Error: %v", path, err)
+	}
+
+	names := map[string]bool{}
+	for i := range config.Plugins {
+		plugin := &config.Plugins[i]
+		if plugin.Name == "" {
+			return nil, fmt.Errorf("plugin %d has no name", i)
+		}
+		if names[plugin.Name] {
+			return nil, fmt.Errorf("plugin '%s' is configured more than once", plugin.Name)
+		}
+		names[plugin.Name] = true
+		if !filepath.IsAbs(plugin.Path) {
+			return nil, fmt.Errorf("plugin '%s' path must be absolute", plugin.Name)
+		}
+		plugin.timeout = defaultTimeout
+		if plugin.Timeout != "" {
+			plugin.timeout, err = time.ParseDuration(plugin.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("plugin '%s' timeout is invalid. Error: %v", plugin.Name, err)
+			}
+		}
+	}
+
+	return &config, nil
+}
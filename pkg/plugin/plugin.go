@@ -0,0 +1,92 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	APIVersion   = "containerregistry.arthurvardevanyan.com/v1beta1"
+	RequestKind  = "PluginRequest"
+	ResponseKind = "PluginResponse"
+	// Limit of stderr Kept for Error Messages
+	maxStderr = 1024
+)
+
+// Request is written to the plugin's stdin as JSON
+type Request struct {
+	APIVersion          string            `json:"apiVersion"`
+	Kind                string            `json:"kind"`
+	Namespace           string            `json:"namespace"`
+	Name                string            `json:"name"`
+	ServiceAccount      string            `json:"serviceAccount"`
+	ServiceAccountToken string            `json:"serviceAccountToken"`
+	Audiences           []string          `json:"audiences"`
+	Parameters          map[string]string `json:"parameters,omitempty"`
+}
+
+// Response is read from the plugin's stdout as JSON
+type Response struct {
+	APIVersion string    `json:"apiVersion"`
+	Kind       string    `json:"kind"`
+	Username   string    `json:"username"`
+	Password   string    `json:"password"`
+	Registries []string  `json:"registries"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// Run executes the plugin with the request on stdin, a non zero exit fails with the plugin's stderr
+func Run(ctx context.Context, plugin PluginConfig, request Request) (*Response, error) {
+	request.APIVersion = APIVersion
+	request.Kind = RequestKind
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := plugin.timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, plugin.Path, plugin.Args...)
+	cmd.Env = plugin.Env
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		message := strings.TrimSpace(stderr.String())
+		if len(message) > maxStderr {
+			message = message[:maxStderr]
+		}
+		if message != "" {
+			return nil, fmt.Errorf("plugin '%s' failed: %v: %s", plugin.Name, err, message)
+		}
+		return nil, fmt.Errorf("plugin '%s' failed: %v", plugin.Name, err)
+	}
+
+	var response Response
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal plugin '%s' response. Error: %v", plugin.Name, err)
+	}
+	if response.Kind != ResponseKind {
+		return nil, fmt.Errorf("plugin '%s' returned kind '%s', expected '%s'", plugin.Name, response.Kind, ResponseKind)
+	}
+	if response.Password == "" || len(response.Registries) == 0 {
+		return nil, fmt.Errorf("plugin '%s' response requires a password and registries", plugin.Name)
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,60 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// Name is the Spec.ContainerRegistry value handled by Provider
+const Name = "plugin"
+
+// Provider runs the external plugins allowed by the controller's plugin config
+type Provider struct {
+	plugins map[string]PluginConfig
+}
+
+func New(config *Config) Provider {
+	plugins := map[string]PluginConfig{}
+	for _, plugin := range config.Plugins {
+		plugins[plugin.Name] = plugin
+	}
+	return Provider{
+		plugins: plugins,
+	}
+}
+
+func (r Provider) Validate(auth *containerregistryv1beta1.Auth) error {
+	if auth.Spec.Plugin.Name == "" {
+		return fmt.Errorf("plugin.name is required")
+	}
+	if _, ok := r.plugins[auth.Spec.Plugin.Name]; !ok {
+		return fmt.Errorf("plugin '%s' is not allowed by the controller's plugin config", auth.Spec.Plugin.Name)
+	}
+	return nil
+}
+
+func (r Provider) Exchange(ctx context.Context, request provider.Request) (*provider.Credentials, error) {
+	plugin := r.plugins[request.Auth.Spec.Plugin.Name]
+
+	response, err := Run(ctx, plugin, Request{
+		Namespace:           request.Auth.Namespace,
+		Name:                request.Auth.Name,
+		ServiceAccount:      request.Auth.Spec.ServiceAccount,
+		ServiceAccountToken: request.SubjectToken,
+		Audiences:           request.Auth.Spec.Audiences,
+		Parameters:          request.Auth.Spec.Plugin.Parameters,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &provider.Credentials{
+		Username:   response.Username,
+		Password:   response.Password,
+		Registries: response.Registries,
+		Expiration: response.Expiration.UTC(),
+	}, nil
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+)
+
+// Entry is a Single Provider Exchange Rendered into the Auth's Secret
+type Entry struct {
+	Name string
+	// Copy of the Auth with the Entry's Provider Configuration, so Providers Read it Unchanged
+	Auth *containerregistryv1beta1.Auth
+}
+
+// Entries Returns One Entry per Spec.Registries, or the Auth Itself when None are Listed
+func Entries(auth *containerregistryv1beta1.Auth) []Entry {
+	if len(auth.Spec.Registries) == 0 {
+		return []Entry{{Name: auth.Spec.ContainerRegistry, Auth: auth}}
+	}
+
+	entries := make([]Entry, 0, len(auth.Spec.Registries))
+	for _, registry := range auth.Spec.Registries {
+		entryAuth := auth.DeepCopy()
+		entryAuth.Spec.Registries = nil
+		if len(registry.Audiences) > 0 {
+			entryAuth.Spec.Audiences = registry.Audiences
+		}
+		entryAuth.Spec.ContainerRegistry = registry.ContainerRegistry
+		entryAuth.Spec.Quay = registry.Quay
+		entryAuth.Spec.GoogleArtifactRegistry = registry.GoogleArtifactRegistry
+		entryAuth.Spec.AWSElasticContainerRegistry = registry.AWSElasticContainerRegistry
+		entryAuth.Spec.AzureContainerRegistry = registry.AzureContainerRegistry
+		entryAuth.Spec.Artifactory = registry.Artifactory
+		entryAuth.Spec.TokenExchange = registry.TokenExchange
+		entryAuth.Spec.Vault = registry.Vault
+		entryAuth.Spec.GoogleSecretManager = registry.GoogleSecretManager
+		entryAuth.Spec.AWSSecretsManager = registry.AWSSecretsManager
+		entryAuth.Spec.Harbor = registry.Harbor
+		entryAuth.Spec.Plugin = registry.Plugin
+		entryAuth.Spec.Mock = registry.Mock
+		entries = append(entries, Entry{Name: registry.Name, Auth: entryAuth})
+	}
+
+	return entries
+}
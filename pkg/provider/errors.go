@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPClient sends the requests of the Providers, with a timeout so an unresponsive endpoint does not stall a reconcile.
+var HTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// HTTPError is returned when a registry or token endpoint responds with an unexpected status.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Body       string
+	// How long the endpoint asked to wait before retrying, from the Retry-After header
+	RetryAfter time.Duration
+}
+
+func NewHTTPError(resp *http.Response, body []byte) *HTTPError {
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       strings.TrimSpace(string(body)),
+		RetryAfter: retryAfter(resp.Header.Get("Retry-After")),
+	}
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as an HTTP date.
+func retryAfter(header string) time.Duration {
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return e.Status
+	}
+	return fmt.Sprintf("%s: %s", e.Status, e.Body)
+}
+
+// HTTPStatusCode returns the status code of the HTTPError in the error's chain, or 0 when there is none.
+func HTTPStatusCode(err error) int {
+	var httpError *HTTPError
+	if errors.As(err, &httpError) {
+		return httpError.StatusCode
+	}
+	return 0
+}
+
+// ConfigMapError is returned when a ConfigMap a provider reads is missing or malformed.
+type ConfigMapError struct {
+	Name string
+	Err  error
+}
+
+func (e *ConfigMapError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConfigMapError) Unwrap() error {
+	return e.Err
+}
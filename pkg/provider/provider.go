@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+)
+
+// Request is everything a Provider is handed when exchanging a token.
+type Request struct {
+	// Client to read any supporting objects (ConfigMaps, Secrets) in the Auth's namespace
+	Client client.Client
+	// The Auth being reconciled
+	Auth *containerregistryv1beta1.Auth
+	// The Kubernetes Service Account token minted for the Auth
+	SubjectToken string
+}
+
+// Credentials are the registry credentials returned by a Provider.
+type Credentials struct {
+	Username string
+	Password string
+	// Registry hosts the credentials are valid for
+	Registries []string
+	// When the credentials stop being valid
+	Expiration time.Time
+}
+
+// Provider exchanges a Kubernetes Service Account token for registry credentials.
+type Provider interface {
+	// Validate checks the provider specific portion of the Auth spec.
+	Validate(auth *containerregistryv1beta1.Auth) error
+	// Exchange trades the subject token for registry credentials.
+	Exchange(ctx context.Context, request Request) (*Credentials, error)
+}
+
+// AudienceDefaulter is implemented by Providers whose token endpoint expects a well known audience.
+type AudienceDefaulter interface {
+	// DefaultAudiences returns the audiences to use when the Auth sets none.
+	DefaultAudiences(auth *containerregistryv1beta1.Auth) []string
+}
+
+// KeyIdentifier is implemented by Providers that sign credentials with a key that can change, such as on restart.
+type KeyIdentifier interface {
+	// KeyID identifies the signing key, credentials signed with another key are not reused.
+	KeyID() string
+}
+
+// Tokenless is implemented by Providers that do not use the subject token, so none is minted for them.
+type Tokenless interface {
+	// Tokenless marks the Provider, it has no behaviour.
+	Tokenless()
+}
+
+// Registry maps Spec.ContainerRegistry values to Providers.
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: map[string]Provider{},
+	}
+}
+
+// Register adds a Provider under the given name, replacing any existing one.
+func (r *Registry) Register(name string, provider Provider) {
+	r.providers[name] = provider
+}
+
+// Get returns the Provider registered under the given name.
+func (r *Registry) Get(name string) (Provider, error) {
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("container registry '%s' is not supported", name)
+	}
+	return provider, nil
+}
@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// UsernamePassword reads a username and password from a JSON object, as stored in cloud secret managers.
+func UsernamePassword(payload []byte, usernameKey string, passwordKey string) (string, string, error) {
+	if usernameKey == "" {
+		usernameKey = "username"
+	}
+	if passwordKey == "" {
+		passwordKey = "password"
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return "", "", fmt.Errorf("secret is not a JSON object. Error: %v", err)
+	}
+	userName, ok := data[usernameKey].(string)
+	if !ok {
+		return "", "", fmt.Errorf("secret key '%s' not found", usernameKey)
+	}
+	password, ok := data[passwordKey].(string)
+	if !ok {
+		return "", "", fmt.Errorf("secret key '%s' not found", passwordKey)
+	}
+	return userName, password, nil
+}
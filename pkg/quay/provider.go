@@ -0,0 +1,61 @@
+package quay
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/jwt"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// Name is the Spec.ContainerRegistry value handled by Provider
+const Name = "quay"
+
+// Robot accounts are named org+robot
+var robotAccountPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*\+[a-z0-9_]+$`)
+
+// Provider exchanges a Kubernetes token for a Quay robot account token
+type Provider struct{}
+
+// NormalizeURL reduces a Quay URL to the host the controller expects, such as quay.io for https://Quay.io/
+func NormalizeURL(url string) string {
+	url = strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+	return strings.ToLower(strings.TrimRight(url, "/"))
+}
+
+func (Provider) Validate(auth *containerregistryv1beta1.Auth) error {
+	if auth.Spec.Quay.RobotAccount == "" {
+		return fmt.Errorf("quay.robotAccount is required")
+	}
+	if !robotAccountPattern.MatchString(auth.Spec.Quay.RobotAccount) {
+		return fmt.Errorf("quay.robotAccount '%s' must be in the form org+robot", auth.Spec.Quay.RobotAccount)
+	}
+	if auth.Spec.Quay.URL == "" {
+		return fmt.Errorf("quay.url is required")
+	}
+	return nil
+}
+
+func (Provider) Exchange(ctx context.Context, request provider.Request) (*provider.Credentials, error) {
+	quaySpec := request.Auth.Spec.Quay
+
+	quayToken, err := GetQuayRobotToken(ctx, request.SubjectToken, quaySpec.RobotAccount, quaySpec.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Generate Quay Token: %w", err)
+	}
+
+	quayTokenExpiration, err := jwt.Expiration(quayToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &provider.Credentials{
+		Username:   quaySpec.RobotAccount,
+		Password:   quayToken,
+		Registries: []string{quaySpec.URL},
+		Expiration: quayTokenExpiration,
+	}, nil
+}
@@ -1,30 +1,41 @@
 package quay
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/tracing"
 )
 
-func GetQuayRobotToken(fedToken string, robotAccount string, url string) (string, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", "http://"+url+"/oauth2/federation/robot/token", nil)
+var tracer = otel.Tracer("github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/quay")
+
+func GetQuayRobotToken(ctx context.Context, fedToken string, robotAccount string, url string) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "GetQuayRobotToken")
+	span.SetAttributes(attribute.String("quay.url", url), attribute.String("quay.robot_account", robotAccount))
+	defer func() { tracing.End(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "http://"+url+"/oauth2/federation/robot/token", nil)
 	if err != nil {
 		return "", err
 	}
 	req.SetBasicAuth(robotAccount, fedToken)
-	resp, err := client.Do(req)
+	resp, err := provider.HTTPClient.Do(req)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.Status != "200 OK" {
-		return "", fmt.Errorf(resp.Status)
+		return "", provider.NewHTTPError(resp, nil)
 	}
 
-	// fmt.Println("Response Status:", resp.Status)
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", err
@@ -32,6 +43,12 @@ func GetQuayRobotToken(fedToken string, robotAccount string, url string) (string
 
 	var result map[string]interface{}
 
-	json.Unmarshal([]byte(body), &result)
-	return result["token"].(string), nil
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("unable to unmarshal quay robot token response. Error: %w", err)
+	}
+	token, ok := result["token"].(string)
+	if !ok {
+		return "", fmt.Errorf("quay robot token response did not contain a token")
+	}
+	return token, nil
 }
@@ -0,0 +1,129 @@
+package tokenexchange
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	coreV1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/jwt"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// Name is the Spec.ContainerRegistry value handled by Provider
+const Name = "tokenExchange"
+
+// Provider exchanges a Kubernetes token at any RFC 8693 token endpoint
+type Provider struct{}
+
+type usernameTemplateData struct {
+	Response map[string]interface{}
+	Claims   map[string]interface{}
+}
+
+func (Provider) Validate(auth *containerregistryv1beta1.Auth) error {
+	tokenExchangeSpec := auth.Spec.TokenExchange
+	if tokenExchangeSpec.TokenEndpoint == "" {
+		return fmt.Errorf("tokenExchange.tokenEndpoint is required")
+	}
+	if len(tokenExchangeSpec.Registries) == 0 {
+		return fmt.Errorf("tokenExchange.registries is required")
+	}
+	if tokenExchangeSpec.ClientAuth != nil && tokenExchangeSpec.ClientAuth.SecretName == "" {
+		return fmt.Errorf("tokenExchange.clientAuth.secretName is required")
+	}
+	if _, err := template.New("username").Option("missingkey=error").Parse(tokenExchangeSpec.UsernameTemplate); err != nil {
+		return fmt.Errorf("tokenExchange.usernameTemplate is invalid. Error: %v", err)
+	}
+	return nil
+}
+
+func defaultString(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func (Provider) Exchange(ctx context.Context, request provider.Request) (*provider.Credentials, error) {
+	tokenExchangeSpec := request.Auth.Spec.TokenExchange
+
+	exchangeRequest := Request{
+		TokenEndpoint:      tokenExchangeSpec.TokenEndpoint,
+		SubjectToken:       request.SubjectToken,
+		SubjectTokenType:   defaultString(tokenExchangeSpec.SubjectTokenType, JWTTokenType),
+		RequestedTokenType: tokenExchangeSpec.RequestedTokenType,
+		Audience:           tokenExchangeSpec.Audience,
+		Resource:           tokenExchangeSpec.Resource,
+		Scope:              tokenExchangeSpec.Scope,
+	}
+
+	if clientAuth := tokenExchangeSpec.ClientAuth; clientAuth != nil {
+		var clientSecret coreV1.Secret
+		err := request.Client.Get(ctx, client.ObjectKey{Name: clientAuth.SecretName, Namespace: request.Auth.Namespace}, &clientSecret)
+		if err != nil {
+			return nil, fmt.Errorf("secret '%s' not found. Error: %w", clientAuth.SecretName, err)
+		}
+		clientIDKey := defaultString(clientAuth.ClientIDKey, "client_id")
+		clientSecretKey := defaultString(clientAuth.ClientSecretKey, "client_secret")
+		clientID, keyFound := clientSecret.Data[clientIDKey]
+		if !keyFound {
+			return nil, fmt.Errorf("secret key '%s' not found", clientIDKey)
+		}
+		exchangeRequest.ClientID = string(clientID)
+		exchangeRequest.ClientSecret = string(clientSecret.Data[clientSecretKey])
+		exchangeRequest.ClientAuthMethod = defaultString(clientAuth.Method, ClientAuthBasic)
+	}
+
+	response, err := Exchange(ctx, exchangeRequest)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Exchange Token: %w", err)
+	}
+
+	tokenPath := defaultString(tokenExchangeSpec.TokenPath, DefaultTokenPath)
+	tokenValue, err := Lookup(response, tokenPath)
+	if err != nil {
+		return nil, err
+	}
+	token, ok := tokenValue.(string)
+	if !ok || token == "" {
+		return nil, fmt.Errorf("'%s' in token exchange response is not a string", tokenPath)
+	}
+
+	// Opaque tokens have no claims, they are only required when the template or expiry needs them
+	claims, claimsErr := jwt.Claims(token)
+
+	userNameTemplate, err := template.New("username").Option("missingkey=error").Parse(defaultString(tokenExchangeSpec.UsernameTemplate, DefaultUserTemplate))
+	if err != nil {
+		return nil, fmt.Errorf("tokenExchange.usernameTemplate is invalid. Error: %v", err)
+	}
+	var userName strings.Builder
+	if err := userNameTemplate.Execute(&userName, usernameTemplateData{Response: response, Claims: claims}); err != nil {
+		return nil, fmt.Errorf("unable to render tokenExchange.usernameTemplate. Error: %v", err)
+	}
+
+	var expiration time.Time
+	if expiresIn, ok := response["expires_in"].(float64); ok {
+		expiration = time.Now().Add(time.Duration(expiresIn) * time.Second).UTC()
+	} else {
+		if claimsErr != nil {
+			return nil, fmt.Errorf("token exchange response has no expires_in and the token is not a JWT. Error: %v", claimsErr)
+		}
+		expiration, err = jwt.Expiration(token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &provider.Credentials{
+		Username:   userName.String(),
+		Password:   token,
+		Registries: tokenExchangeSpec.Registries,
+		Expiration: expiration,
+	}, nil
+}
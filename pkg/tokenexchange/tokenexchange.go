@@ -0,0 +1,107 @@
+package tokenexchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// https://datatracker.ietf.org/doc/html/rfc8693
+
+const (
+	GrantType           = "urn:ietf:params:oauth:grant-type:token-exchange"
+	JWTTokenType        = "urn:ietf:params:oauth:token-type:jwt"
+	ClientAuthBasic     = "basic"
+	ClientAuthPost      = "post"
+	DefaultTokenPath    = "access_token"
+	DefaultUserTemplate = "token"
+)
+
+type Request struct {
+	TokenEndpoint      string
+	SubjectToken       string
+	SubjectTokenType   string
+	RequestedTokenType string
+	Audience           []string
+	Resource           []string
+	Scope              string
+	ClientID           string
+	ClientSecret       string
+	ClientAuthMethod   string
+}
+
+// Exchange performs the token exchange and returns the decoded JSON response
+func Exchange(ctx context.Context, request Request) (map[string]interface{}, error) {
+	form := url.Values{}
+	form.Set("grant_type", GrantType)
+	form.Set("subject_token", request.SubjectToken)
+	form.Set("subject_token_type", request.SubjectTokenType)
+	if request.RequestedTokenType != "" {
+		form.Set("requested_token_type", request.RequestedTokenType)
+	}
+	for _, audience := range request.Audience {
+		form.Add("audience", audience)
+	}
+	for _, resource := range request.Resource {
+		form.Add("resource", resource)
+	}
+	if request.Scope != "" {
+		form.Set("scope", request.Scope)
+	}
+	if request.ClientID != "" && request.ClientAuthMethod == ClientAuthPost {
+		form.Set("client_id", request.ClientID)
+		form.Set("client_secret", request.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", request.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if request.ClientID != "" && request.ClientAuthMethod != ClientAuthPost {
+		req.SetBasicAuth(url.QueryEscape(request.ClientID), url.QueryEscape(request.ClientSecret))
+	}
+	resp, err := provider.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, provider.NewHTTPError(resp, body)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal token exchange response. Error: %v", err)
+	}
+	return result, nil
+}
+
+// Lookup follows a dot separated path through nested JSON objects
+func Lookup(response map[string]interface{}, path string) (interface{}, error) {
+	var value interface{} = response
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("'%s' not found in token exchange response", path)
+		}
+		value, ok = object[key]
+		if !ok {
+			return nil, fmt.Errorf("'%s' not found in token exchange response", path)
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,97 @@
+// Package tracing configures the OpenTelemetry tracer provider and trace context propagation.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// Spans are not exported
+	ExporterNone = "none"
+	// Spans are exported to an OTLP gRPC collector
+	ExporterOTLP = "otlp"
+	// Spans are printed to stdout
+	ExporterStdout = "stdout"
+)
+
+const serviceName = "container-registry-k8s-auth-controller"
+
+// Options select where spans are exported.
+type Options struct {
+	// none, otlp or stdout
+	Exporter string
+	// The OTLP collector's host:port, OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4317 when empty
+	Endpoint string
+	// Connect to the OTLP collector without TLS
+	Insecure bool
+	// Fraction of traces sampled, between 0 and 1
+	SampleRatio float64
+}
+
+// Setup installs the global tracer provider and W3C trace context propagation,
+// and instruments http.DefaultTransport so outbound calls to registries carry the trace context.
+// The returned function flushes and stops the exporter.
+func Setup(ctx context.Context, options Options) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch options.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporterOptions := []otlptracegrpc.Option{}
+		if options.Endpoint != "" {
+			exporterOptions = append(exporterOptions, otlptracegrpc.WithEndpoint(options.Endpoint))
+		}
+		if options.Insecure {
+			exporterOptions = append(exporterOptions, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, exporterOptions...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("tracing exporter '%s' is not supported, use %s, %s or %s", options.Exporter, ExporterNone, ExporterOTLP, ExporterStdout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s trace exporter. Error: %w", options.Exporter, err)
+	}
+
+	traceResource, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(traceResource),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SampleRatio))),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	http.DefaultTransport = otelhttp.NewTransport(http.DefaultTransport)
+
+	return tracerProvider.Shutdown, nil
+}
+
+// End records the error on the span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,90 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	containerregistryv1beta1 "github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/api/v1beta1"
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+// Name is the Spec.ContainerRegistry value handled by Provider
+const Name = "vault"
+
+// Used when neither the secret nor the login token has a lease
+const defaultLease = time.Hour
+
+// Provider logs in to Vault with a Kubernetes token and reads registry credentials
+type Provider struct{}
+
+func (Provider) Validate(auth *containerregistryv1beta1.Auth) error {
+	vaultSpec := auth.Spec.Vault
+	if vaultSpec.Address == "" {
+		return fmt.Errorf("vault.address is required")
+	}
+	if vaultSpec.Role == "" {
+		return fmt.Errorf("vault.role is required")
+	}
+	if vaultSpec.Path == "" {
+		return fmt.Errorf("vault.path is required")
+	}
+	if len(vaultSpec.Registries) == 0 {
+		return fmt.Errorf("vault.registries is required")
+	}
+	return nil
+}
+
+func defaultString(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func (Provider) Exchange(ctx context.Context, request provider.Request) (*provider.Credentials, error) {
+	vaultSpec := request.Auth.Spec.Vault
+
+	vaultClient := Client{
+		Address:   vaultSpec.Address,
+		Namespace: vaultSpec.Namespace,
+	}
+
+	authMethod := defaultString(vaultSpec.AuthMethod, "kubernetes")
+	tokenLease, err := vaultClient.Login(ctx, defaultString(vaultSpec.AuthMount, authMethod), vaultSpec.Role, request.SubjectToken)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Login to Vault: %w", err)
+	}
+
+	data, secretLease, err := vaultClient.Read(ctx, vaultSpec.Path)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to Read Vault Secret: %w", err)
+	}
+
+	userNameKey := defaultString(vaultSpec.UsernameKey, "username")
+	passwordKey := defaultString(vaultSpec.PasswordKey, "password")
+	userName, ok := data[userNameKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("vault secret key '%s' not found", userNameKey)
+	}
+	password, ok := data[passwordKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("vault secret key '%s' not found", passwordKey)
+	}
+
+	// Dynamic secrets rotate with their lease, static secrets are re-read when the login token expires
+	lease := secretLease
+	if lease == 0 {
+		lease = tokenLease
+	}
+	if lease == 0 {
+		lease = defaultLease
+	}
+
+	return &provider.Credentials{
+		Username:   userName,
+		Password:   password,
+		Registries: vaultSpec.Registries,
+		Expiration: time.Now().Add(lease).UTC(),
+	}, nil
+}
@@ -0,0 +1,104 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ArthurVardevanyan/container-registry-k8s-auth-controller/pkg/provider"
+)
+
+type Client struct {
+	Address   string
+	Namespace string
+	Token     string
+}
+
+type Secret struct {
+	Data          map[string]interface{} `json:"data"`
+	LeaseDuration int64                  `json:"lease_duration"`
+	Auth          *struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int64  `json:"lease_duration"`
+	} `json:"auth"`
+}
+
+func (r *Client) do(ctx context.Context, method string, path string, payload interface{}) (*Secret, error) {
+	var body io.Reader
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(payloadBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(r.Address, "/")+"/v1/"+strings.TrimPrefix(path, "/"), body)
+	if err != nil {
+		return nil, err
+	}
+	if r.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", r.Namespace)
+	}
+	if r.Token != "" {
+		req.Header.Set("X-Vault-Token", r.Token)
+	}
+	resp, err := provider.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, provider.NewHTTPError(resp, respBody)
+	}
+
+	var secret Secret
+	if err := json.Unmarshal(respBody, &secret); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal vault response. Error: %v", err)
+	}
+	return &secret, nil
+}
+
+// Login authenticates with a Kubernetes or JWT auth method, storing the client token on success.
+// Returns how long the client token is valid for.
+func (r *Client) Login(ctx context.Context, authMount string, role string, jwt string) (time.Duration, error) {
+	secret, err := r.do(ctx, "POST", "auth/"+strings.Trim(authMount, "/")+"/login", map[string]string{
+		"role": role,
+		"jwt":  jwt,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return 0, fmt.Errorf("vault login response did not contain a client token")
+	}
+	r.Token = secret.Auth.ClientToken
+	return time.Duration(secret.Auth.LeaseDuration) * time.Second, nil
+}
+
+// Read returns the data at the path, unwrapping KV version 2 responses.
+// The lease duration is zero for secrets without a lease.
+func (r *Client) Read(ctx context.Context, path string) (map[string]interface{}, time.Duration, error) {
+	secret, err := r.do(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+	return data, time.Duration(secret.LeaseDuration) * time.Second, nil
+}